@@ -0,0 +1,58 @@
+package api
+
+import (
+	"github.com/imroc/req"
+)
+
+// GetAlbumPages of all photos of user, including the photos not posted, e.g. avatars and covers.
+// page starts from 1
+func (api *WeiboAPI) GetAlbumPages(uid string, page int) (*WeiboUserListPageIndex, error) {
+	containerId, err := api.GetTabContainerId(uid, TAB_ALBUM)
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.get(
+		"https://m.weibo.cn/api/container/getSecond",
+		req.QueryParam{
+			"containerid": containerId + "_-_photoall",
+			"count":       24,
+			"page":        page,
+		},
+		req.Header{
+			"Referer":    "https://m.weibo.cn/",
+			"MWeibo-Pwa": "1",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboUserListPageIndex{}
+	if err = api.decode("container/getSecond?containerid=photoall", res, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// GetVideoPages of user posts with video, page starts from 1
+func (api *WeiboAPI) GetVideoPages(uid string, page int) (*WeiboUserListPageIndex, error) {
+	containerId, err := api.GetTabContainerId(uid, TAB_VIDEO)
+	if err != nil {
+		return nil, err
+	}
+	return api.GetContainerPages(uid, containerId, TAB_VIDEO, page)
+}
+
+// Photos in the cards of page, including the photos nested in card groups
+func (r *WeiboUserListPageIndex) Photos() (rt []*CardPic) {
+	for _, card := range r.Data.Cards {
+		for i := range card.Pics {
+			rt = append(rt, &card.Pics[i])
+		}
+		for _, group := range card.CardGroup {
+			for i := range group.Pics {
+				rt = append(rt, &group.Pics[i])
+			}
+		}
+	}
+	return rt
+}
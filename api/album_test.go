@@ -0,0 +1,44 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeiboUserListPageIndex_Photos(t *testing.T) {
+	assert := assert.New(t)
+
+	page, err := UnmarshalWeiboUserListPageIndex([]byte(`{"ok":1,"data":{"cards":[
+		{"card_type":47,"pics":[{"pic_id":"p1","pic_big":"https://wx1.sinaimg.cn/large/p1.jpg"}]},
+		{"card_type":11,"card_group":[
+			{"card_type":47,"pics":[
+				{"pic_id":"p2","pic_big":"https://wx1.sinaimg.cn/large/p2.jpg","mblog":{"id":"123"}}
+			]}
+		]}
+	]}}`))
+	assert.Nil(err)
+	photos := page.Photos()
+	assert.Equal(2, len(photos))
+	assert.Equal("p1", photos[0].PicID)
+	assert.Nil(photos[0].Mblog)
+	assert.Equal("123", *photos[1].Mblog.ID)
+}
+
+func TestPageInfo_VideoURL(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		name     string
+		pageInfo PageInfo
+		want     string
+	}{
+		{"not video", PageInfo{Type: "article", MediaInfo: &MediaInfo{StreamURL: "a"}}, ""},
+		{"720p first", PageInfo{Type: "video", Urls: &Urls{Mp4720PMp4: "720", Mp4HDMp4: "hd"}, MediaInfo: &MediaInfo{StreamURL: "a"}}, "720"},
+		{"hd", PageInfo{Type: "video", Urls: &Urls{Mp4HDMp4: "hd", Mp4LdMp4: "ld"}}, "hd"},
+		{"stream", PageInfo{Type: "video", MediaInfo: &MediaInfo{StreamURL: "a"}}, "a"},
+		{"stream hd", PageInfo{Type: "video", MediaInfo: &MediaInfo{StreamURL: "a", StreamURLHD: "b"}}, "b"},
+	}
+	for _, tt := range tests {
+		assert.Equal(tt.want, tt.pageInfo.VideoURL(), tt.name)
+	}
+}
@@ -1,16 +1,48 @@
 package api
 
 import (
+	"sync"
 	"time"
 
+	"github.com/imroc/req"
 	"github.com/patrickmn/go-cache"
 )
 
 type WeiboAPI struct {
 	cache *cache.Cache
+	// schema drift in strict decode mode, nil if disabled
+	drift *schemaDrift
+	// minimal interval between requests, shared by all goroutines using this instance
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
 }
 
 // NewWeiboAPI to create api instance for weibo
 func NewWeiboAPI() *WeiboAPI {
 	return &WeiboAPI{cache: cache.New(5*time.Minute, 1*time.Hour)}
 }
+
+// SetRequestInterval to limit the request rate, at most one request per interval
+func (api *WeiboAPI) SetRequestInterval(interval time.Duration) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.interval = interval
+}
+
+// get with request rate limited
+func (api *WeiboAPI) get(url string, v ...interface{}) (*req.Resp, error) {
+	api.mu.Lock()
+	now := time.Now()
+	wait := api.next.Sub(now)
+	if wait > 0 {
+		api.next = api.next.Add(api.interval)
+	} else {
+		api.next = now.Add(api.interval)
+	}
+	api.mu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return req.Get(url, v...)
+}
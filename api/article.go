@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/imroc/req"
+)
+
+// ARTICLE_OK_CODE is the business code of a successful headline article response
+const ARTICLE_OK_CODE = "100000"
+
+var articleIdFromURL = regexp.MustCompile(`/(?:id|article/m/show/id)/(\d+)`)
+
+var articleImageSource = regexp.MustCompile(`<img[^>]+src="([^"]+)"`)
+
+// GetArticleId from page info of a teaser post, return empty string if it is not a headline article
+func GetArticleId(pageInfo *PageInfo) string {
+	if pageInfo == nil || pageInfo.Type != "article" {
+		return ""
+	}
+	if pageInfo.PageID != nil && len(*pageInfo.PageID) > 0 {
+		return *pageInfo.PageID
+	}
+	if pageInfo.ObjectID != nil {
+		// object id looks like '1022:2309404629234345640024'
+		parts := strings.Split(*pageInfo.ObjectID, ":")
+		return parts[len(parts)-1]
+	}
+	if matched := articleIdFromURL.FindStringSubmatch(pageInfo.PageURL); len(matched) > 1 {
+		return matched[1]
+	}
+	return ""
+}
+
+// GetArticle (头条文章) full content by article id
+func (api *WeiboAPI) GetArticle(articleId string) (*WeiboArticle, error) {
+	res, err := api.get(
+		"https://card.weibo.com/article/m/aj/detail",
+		req.QueryParam{
+			"id": articleId,
+		},
+		req.Header{
+			"Referer": fmt.Sprintf("https://card.weibo.com/article/m/show/id/%s", articleId),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboArticle{}
+	if err = api.decode("article/m/aj/detail", res, body); err != nil {
+		return nil, err
+	}
+	if body.Code != ARTICLE_OK_CODE {
+		return nil, fmt.Errorf("fetch article '%s' failed: %s", articleId, body.Msg)
+	}
+	if body.Data == nil {
+		return nil, errors.New("article response without data")
+	}
+	return body, nil
+}
+
+// Images in article content, cover image is not included
+func (d *ArticleData) Images() (rt []string) {
+	for _, matched := range articleImageSource.FindAllStringSubmatch(d.Content, -1) {
+		rt = append(rt, matched[1])
+	}
+	return rt
+}
+
+func UnmarshalWeiboArticle(data []byte) (WeiboArticle, error) {
+	var r WeiboArticle
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *WeiboArticle) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type WeiboArticle struct {
+	Code string       `json:"code"`
+	Msg  string       `json:"msg"`
+	Data *ArticleData `json:"data,omitempty"`
+}
+
+type ArticleData struct {
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	Summary   string          `json:"summary"`
+	Content   string          `json:"content"`
+	CoverImg  *ArticleCover   `json:"cover_img,omitempty"`
+	CreateAt  string          `json:"create_at"`
+	UpdateAt  string          `json:"update_at"`
+	ReadCount interface{}     `json:"read_count"`
+	Userinfo  ArticleUserInfo `json:"userinfo"`
+	URL       string          `json:"url"`
+}
+
+type ArticleCover struct {
+	Image ArticleImage `json:"image"`
+}
+
+type ArticleImage struct {
+	URL    string `json:"url"`
+	Width  int64  `json:"width"`
+	Height int64  `json:"height"`
+}
+
+type ArticleUserInfo struct {
+	Uid             int64  `json:"uid"`
+	ScreenName      string `json:"screen_name"`
+	ProfileImageURL string `json:"profile_image_url"`
+	Description     string `json:"description"`
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetArticleId(t *testing.T) {
+	assert := assert.New(t)
+
+	objectId := "1022:2309404629234345640024"
+	pageId := "2309404629234345640025"
+
+	tests := []struct {
+		name     string
+		pageInfo *PageInfo
+		want     string
+	}{
+		{"nil page info", nil, ""},
+		{"not article", &PageInfo{Type: "video", ObjectID: &objectId}, ""},
+		{"with page id", &PageInfo{Type: "article", PageID: &pageId, ObjectID: &objectId}, pageId},
+		{"with object id", &PageInfo{Type: "article", ObjectID: &objectId}, "2309404629234345640024"},
+		{"with page url", &PageInfo{Type: "article", PageURL: "https://card.weibo.com/article/m/show/id/2309404629234345640026"}, "2309404629234345640026"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(tt.want, GetArticleId(tt.pageInfo))
+		})
+	}
+}
+
+func TestArticleData_Images(t *testing.T) {
+	assert := assert.New(t)
+
+	data := &ArticleData{
+		Content: `<p>hello</p><img class="a" src="https://wx1.sinaimg.cn/large/1.jpg"><p><img src="https://wx1.sinaimg.cn/large/2.jpg" /></p>`,
+	}
+	assert.Equal([]string{
+		"https://wx1.sinaimg.cn/large/1.jpg",
+		"https://wx1.sinaimg.cn/large/2.jpg",
+	}, data.Images())
+}
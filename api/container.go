@@ -2,19 +2,34 @@ package api
 
 import (
 	"encoding/json"
-	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/imroc/req"
 	"github.com/patrickmn/go-cache"
 )
 
-// GetContainerId of uid
-func (api *WeiboAPI) GetContainerId(uid string) (string, error) {
-	key := "weibo:containerid:" + uid
+// server side filters of user posts, as the suffix of filter container id
+const (
+	FILTER_ORIGINAL = "WEIBO_ORI"
+	FILTER_PICTURE  = "WEIBO_PIC"
+	FILTER_VIDEO    = "WEIBO_VIDEO"
+)
+
+// tab keys of user index
+const (
+	TAB_WEIBO = "weibo"
+	TAB_ALBUM = "album"
+	TAB_VIDEO = "video"
+)
+
+// GetUserIndex of uid, contains user info and tabs
+func (api *WeiboAPI) GetUserIndex(uid string) (*WeiboUserIndex, error) {
+	key := "weibo:index:" + uid
 	if value, found := api.cache.Get(key); found {
-		return value.(string), nil
+		return value.(*WeiboUserIndex), nil
 	}
-	res, err := req.Get(
+	res, err := api.get(
 		"https://m.weibo.cn/api/container/getIndex",
 		req.QueryParam{
 			"type":  "uid",
@@ -25,23 +40,57 @@ func (api *WeiboAPI) GetContainerId(uid string) (string, error) {
 		},
 	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	body := &WeiboUserIndex{}
-	if err := res.ToJSON(body); err != nil {
+	if err := api.decode("container/getIndex?type=uid", res, body); err != nil {
+		return nil, err
+	}
+	api.cache.Set(key, body, cache.DefaultExpiration)
+	return body, nil
+}
+
+// GetContainerId of uid
+func (api *WeiboAPI) GetContainerId(uid string) (string, error) {
+	return api.GetTabContainerId(uid, TAB_WEIBO)
+}
+
+// GetTabContainerId of uid by tab key, e.g. 'weibo', 'album' or 'video'
+func (api *WeiboAPI) GetTabContainerId(uid string, tabKey string) (string, error) {
+	index, err := api.GetUserIndex(uid)
+	if err != nil {
 		return "", err
 	}
+	if tab := index.Tab(tabKey); tab != nil {
+		return tab.Containerid, nil
+	}
+	return "", fmt.Errorf("not found correct container for '%s'", tabKey)
+}
 
-	for _, tab := range body.Data.TabsInfo.Tabs {
-		if tab.TabKey == "weibo" {
-			value := tab.Containerid
-			api.cache.Set(key, value, cache.DefaultExpiration)
-			return value, nil
+// GetFilterContainerId of uid, return empty string if weibo does not provide the filter for user
+func (api *WeiboAPI) GetFilterContainerId(uid string, filter string) (string, error) {
+	index, err := api.GetUserIndex(uid)
+	if err != nil {
+		return "", err
+	}
+	if tab := index.Tab(TAB_WEIBO); tab != nil {
+		for _, group := range tab.FilterGroup {
+			if strings.HasSuffix(group.Containerid, "_"+filter) {
+				return group.Containerid, nil
+			}
 		}
 	}
+	return "", nil
+}
 
-	return "", errors.New("not found correct container for 'weibo'")
-
+// Tab of user index by tab key, nil if user has no such tab
+func (r *WeiboUserIndex) Tab(tabKey string) *Tab {
+	for i, tab := range r.Data.TabsInfo.Tabs {
+		if tab.TabKey == tabKey {
+			return &r.Data.TabsInfo.Tabs[i]
+		}
+	}
+	return nil
 }
 
 func UnmarshalWeiboUserIndex(data []byte) (WeiboUserIndex, error) {
@@ -192,3 +241,15 @@ type ToolbarMenuUserInfo struct {
 	AllowMsg        int64  `json:"allow_msg"`
 	SpecialFollow   bool   `json:"special_follow"`
 }
+
+// GetUserProfile of uid, contains description, avatar, cover, counts and verified reason
+func (api *WeiboAPI) GetUserProfile(uid string) (*DataUserInfo, error) {
+	index, err := api.GetUserIndex(uid)
+	if err != nil {
+		return nil, err
+	}
+	if index.Data.UserInfo.ID == 0 {
+		return nil, fmt.Errorf("user '%s' not found", uid)
+	}
+	return &index.Data.UserInfo, nil
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/imroc/req"
+)
+
+// item names of user detail page
+const (
+	DETAIL_LOCATION      = "所在地"
+	DETAIL_BIRTHDAY      = "生日"
+	DETAIL_COMPANY       = "公司"
+	DETAIL_REGISTERED_AT = "注册时间"
+	DETAIL_CREDIT        = "阳光信用"
+)
+
+// item names of education in user detail page
+var detailEducations = []string{"学校", "大学", "高中", "初中", "小学", "中专技校", "海外"}
+
+// GetUserDetail (基本资料) of uid, items hidden by the privacy settings of user are absent
+func (api *WeiboAPI) GetUserDetail(uid string) (*UserDetail, error) {
+	containerId := fmt.Sprintf("230283%s_-_INFO", uid)
+	res, err := api.get(
+		"https://m.weibo.cn/api/container/getIndex",
+		req.QueryParam{
+			"containerid": containerId,
+			"title":       "基本资料",
+			"luicode":     "10000011",
+			"lfid":        fmt.Sprintf("230283%s", uid),
+		},
+		req.Header{
+			"Referer":    "https://m.weibo.cn/",
+			"MWeibo-Pwa": "1",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboUserListPageIndex{}
+	if err = api.decode("container/getIndex?containerid=INFO", res, body); err != nil {
+		return nil, err
+	}
+	if body.Ok != 1 {
+		return nil, fmt.Errorf("detail of user '%s' not found", uid)
+	}
+	return NewUserDetail(body), nil
+}
+
+// UserDetail items of user, item name -> content, multiple contents of same name are joined by '; '
+type UserDetail struct {
+	Items map[string]string
+}
+
+// NewUserDetail from the info cards
+func NewUserDetail(page *WeiboUserListPageIndex) *UserDetail {
+	rt := &UserDetail{Items: map[string]string{}}
+	for _, card := range page.Data.Cards {
+		for _, item := range card.CardGroup {
+			if item.CardType != CARD_TYPE_INFO || item.ItemName == nil || item.ItemContent == nil {
+				continue
+			}
+			name, content := strings.TrimSpace(*item.ItemName), strings.TrimSpace(*item.ItemContent)
+			if len(name) == 0 || len(content) == 0 {
+				continue
+			}
+			if value, found := rt.Items[name]; found {
+				content = value + "; " + content
+			}
+			rt.Items[name] = content
+		}
+	}
+	return rt
+}
+
+func (d *UserDetail) Location() string {
+	return d.Items[DETAIL_LOCATION]
+}
+
+func (d *UserDetail) Birthday() string {
+	return d.Items[DETAIL_BIRTHDAY]
+}
+
+func (d *UserDetail) Company() string {
+	return d.Items[DETAIL_COMPANY]
+}
+
+func (d *UserDetail) RegisteredAt() string {
+	return d.Items[DETAIL_REGISTERED_AT]
+}
+
+func (d *UserDetail) CreditLevel() string {
+	return d.Items[DETAIL_CREDIT]
+}
+
+// Education of all levels, e.g. '大学: 北京大学; 高中: 北京四中'
+func (d *UserDetail) Education() string {
+	var rt []string
+	for _, name := range detailEducations {
+		if value, found := d.Items[name]; found {
+			rt = append(rt, name+": "+value)
+		}
+	}
+	return strings.Join(rt, "; ")
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserDetail(t *testing.T) {
+	assert := assert.New(t)
+
+	page, err := UnmarshalWeiboUserListPageIndex([]byte(`{"ok":1,"data":{"cards":[
+		{"card_type":11,"card_group":[
+			{"card_type":41,"item_name":"所在地","item_content":"北京 海淀区"},
+			{"card_type":41,"item_name":"生日","item_content":"1990-01-01 摩羯座"}
+		]},
+		{"card_type":11,"card_group":[
+			{"card_type":41,"item_name":"大学","item_content":"北京大学"},
+			{"card_type":41,"item_name":"大学","item_content":"清华大学"},
+			{"card_type":41,"item_name":"高中","item_content":"北京四中"},
+			{"card_type":41,"item_name":"阳光信用","item_content":"信用极好"}
+		]}
+	]}}`))
+	assert.Nil(err)
+	detail := NewUserDetail(&page)
+	assert.Equal("北京 海淀区", detail.Location())
+	assert.Equal("1990-01-01 摩羯座", detail.Birthday())
+	assert.Equal("", detail.Company())
+	assert.Equal("信用极好", detail.CreditLevel())
+	assert.Equal("大学: 北京大学; 清华大学; 高中: 北京四中", detail.Education())
+}
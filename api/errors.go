@@ -0,0 +1,32 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// StatusError of unexpected http status code returned by weibo
+type StatusError struct {
+	Endpoint   string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("weibo api '%s' responded with status %d", e.Endpoint, e.StatusCode)
+}
+
+// IsTransient error which may succeed by retrying, e.g. network failure, rate limit or server error
+func IsTransient(err error) bool {
+	switch e := err.(type) {
+	case *StatusError:
+		// weibo responds 418 when requests are too frequent
+		return e.StatusCode == http.StatusTeapot ||
+			e.StatusCode == http.StatusTooManyRequests ||
+			e.StatusCode >= http.StatusInternalServerError
+	case net.Error:
+		return true
+	}
+	return err == io.EOF || err == io.ErrUnexpectedEOF
+}
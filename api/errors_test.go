@@ -0,0 +1,30 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransient(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"too frequent", &StatusError{"feed/friends", 418}, true},
+		{"server error", &StatusError{"feed/friends", 502}, true},
+		{"forbidden", &StatusError{"feed/friends", 403}, false},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"other", errors.New("not found correct container for 'weibo'"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(tt.want, IsTransient(tt.err))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/imroc/req"
+)
+
+// GetFavorites (收藏) of the account, newest favorite first, page starts from 1, empty if no more.
+// need the 'SUB' part of cookie
+func (api *WeiboAPI) GetFavorites(cookieSub string, uid string, page int) (*WeiboFavorites, error) {
+	res, err := api.get(
+		"https://weibo.com/ajax/favorites/all_fav",
+		req.QueryParam{
+			"uid":  uid,
+			"page": page,
+		},
+		req.Header{
+			"Referer": "https://weibo.com/",
+			"Cookie":  fmt.Sprintf("SUB=%s", cookieSub),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboFavorites{}
+	if err = api.decode("ajax/favorites/all_fav", res, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func UnmarshalWeiboFavorites(data []byte) (WeiboFavorites, error) {
+	var r WeiboFavorites
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *WeiboFavorites) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type WeiboFavorites struct {
+	Ok int64 `json:"ok"`
+	// Data of favorite posts, in the format of weibo.com
+	Data []*Mblog `json:"data"`
+}
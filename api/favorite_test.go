@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalWeiboFavorites(t *testing.T) {
+	assert := assert.New(t)
+
+	body, err := UnmarshalWeiboFavorites([]byte(`{"ok":1,"data":[{
+		"id":4823456789012345,
+		"created_at":"Mon Jan 02 15:04:05 +0800 2023",
+		"favorited_time":"Tue Feb 07 10:00:00 +0800 2023",
+		"text":"hello",
+		"user":{"id":123,"screen_name":"a"},
+		"pic_ids":["p2","p1"],
+		"pic_infos":{
+			"p1":{"largest":{"url":"https://wx1.sinaimg.cn/large/p1.jpg"}},
+			"p2":{"largest":{"url":"https://wx1.sinaimg.cn/large/p2.jpg"}}
+		},
+		"retweeted_status":{"id":4823456789000000,"text":"origin"}
+	}]}`))
+	assert.Nil(err)
+	assert.Equal(1, len(body.Data))
+	mblog := body.Data[0]
+	assert.Equal("4823456789012345", *mblog.ID)
+	assert.Equal("Tue Feb 07 10:00:00 +0800 2023", *mblog.FavoritedTime)
+	assert.Equal("4823456789000000", mblog.RetweetedStatus.ID)
+	assert.Equal([]Pic{
+		{PID: "p2", URL: "https://wx1.sinaimg.cn/large/p2.jpg"},
+		{PID: "p1", URL: "https://wx1.sinaimg.cn/large/p1.jpg"},
+	}, mblog.AllPics())
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/imroc/req"
+)
+
+// GetLoginUid of the account, need the 'SUB' part of cookie
+func (api *WeiboAPI) GetLoginUid(cookieSub string) (string, error) {
+	res, err := api.get(
+		"https://m.weibo.cn/api/config",
+		req.Header{
+			"Referer": "https://m.weibo.cn/",
+			"Cookie":  fmt.Sprintf("SUB=%s", cookieSub),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	body := &WeiboConfig{}
+	if err = api.decode("api/config", res, body); err != nil {
+		return "", err
+	}
+	if !body.Data.Login || len(body.Data.Uid) == 0 {
+		return "", errors.New("not logged in, the cookie may be expired")
+	}
+	return body.Data.Uid, nil
+}
+
+// GetFollowings of user, page starts from 1, empty if no more.
+// the 'SUB' part of cookie is needed for the complete list, could be empty for a public part
+func (api *WeiboAPI) GetFollowings(cookieSub string, uid string, page int) ([]*User, error) {
+	body, err := api.getUserList(cookieSub, req.QueryParam{
+		"containerid": fmt.Sprintf("231051_-_followers_-_%s", uid),
+		"page":        page,
+	}, "container/getIndex?containerid=followers")
+	if err != nil || body == nil {
+		return nil, err
+	}
+	return body.Users(), nil
+}
+
+// GetFollowers of user, the list of fans is paged by 'since_id' returned by the previous page,
+// 0 for the first page. nextSinceId is 0 after the last page.
+// the 'SUB' part of cookie is needed for the complete list, could be empty for a public part
+func (api *WeiboAPI) GetFollowers(cookieSub string, uid string, sinceId int64) (users []*User, nextSinceId int64, err error) {
+	query := req.QueryParam{
+		"containerid": fmt.Sprintf("231051_-_fans_-_%s", uid),
+	}
+	if sinceId > 0 {
+		query["since_id"] = sinceId
+	}
+	body, err := api.getUserList(cookieSub, query, "container/getIndex?containerid=fans")
+	if err != nil || body == nil {
+		return nil, 0, err
+	}
+	return body.Users(), body.Data.CardlistInfo.SinceID, nil
+}
+
+// getUserList page, nil if there is no more page
+func (api *WeiboAPI) getUserList(cookieSub string, query req.QueryParam, endpoint string) (*WeiboUserListPageIndex, error) {
+	header := req.Header{
+		"Referer":    "https://m.weibo.cn/",
+		"MWeibo-Pwa": "1",
+	}
+	if len(cookieSub) > 0 {
+		header["Cookie"] = fmt.Sprintf("SUB=%s", cookieSub)
+	}
+	res, err := api.get("https://m.weibo.cn/api/container/getIndex", query, header)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboUserListPageIndex{}
+	if err = api.decode(endpoint, res, body); err != nil {
+		return nil, err
+	}
+	// weibo responds 'ok: 0' after the last page
+	if body.Ok != 1 {
+		return nil, nil
+	}
+	return body, nil
+}
+
+// GetGroupMembers of follow group (分组) of the account, page starts from 1, need the 'SUB' part of cookie
+func (api *WeiboAPI) GetGroupMembers(cookieSub string, gid string, page int) (*WeiboGroupMembers, error) {
+	res, err := api.get(
+		"https://weibo.com/ajax/profile/followContent",
+		req.QueryParam{
+			"gid":  gid,
+			"page": page,
+		},
+		req.Header{
+			"Referer": "https://weibo.com/",
+			"Cookie":  fmt.Sprintf("SUB=%s", cookieSub),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboGroupMembers{}
+	if err = api.decode("ajax/profile/followContent", res, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Users in the card groups of page, e.g. followings
+func (r *WeiboUserListPageIndex) Users() (rt []*User) {
+	for _, card := range r.Data.Cards {
+		for i := range card.CardGroup {
+			if user := card.CardGroup[i].User; user != nil && user.ID != 0 {
+				rt = append(rt, user)
+			}
+		}
+	}
+	return rt
+}
+
+// Uid as string
+func (r *User) Uid() string {
+	return fmt.Sprint(r.ID)
+}
+
+func UnmarshalWeiboConfig(data []byte) (WeiboConfig, error) {
+	var r WeiboConfig
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *WeiboConfig) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type WeiboConfig struct {
+	Ok   int64      `json:"ok"`
+	Data ConfigData `json:"data"`
+}
+
+type ConfigData struct {
+	Login bool   `json:"login"`
+	Uid   string `json:"uid"`
+	St    string `json:"st"`
+}
+
+func UnmarshalWeiboGroupMembers(data []byte) (WeiboGroupMembers, error) {
+	var r WeiboGroupMembers
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *WeiboGroupMembers) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type WeiboGroupMembers struct {
+	Ok   int64            `json:"ok"`
+	Data GroupMembersData `json:"data"`
+}
+
+type GroupMembersData struct {
+	Follows GroupFollows `json:"follows"`
+}
+
+type GroupFollows struct {
+	Users       []User `json:"users"`
+	TotalNumber int64  `json:"total_number"`
+}
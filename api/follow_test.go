@@ -0,0 +1,25 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeiboUserListPageIndex_Fans(t *testing.T) {
+	assert := assert.New(t)
+
+	body, err := UnmarshalWeiboUserListPageIndex([]byte(`{"ok":1,"data":{
+		"cardlistInfo":{"containerid":"231051_-_fans_-_1","since_id":21},
+		"cards":[{"card_type":11,"card_group":[
+			{"card_type":10,"user":{"id":2,"screen_name":"b"}},
+			{"card_type":10,"user":{"id":3,"screen_name":"c"}}
+		]}]
+	}}`))
+	assert.Nil(err)
+	users := body.Users()
+	assert.Equal(2, len(users))
+	assert.Equal("2", users[0].Uid())
+	// the cursor of next page is not the page number
+	assert.Equal(int64(21), body.Data.CardlistInfo.SinceID)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/imroc/req"
+)
+
+// GetGroups (分组) of friends of the account, need the 'SUB' part of cookie
+func (api *WeiboAPI) GetGroups(cookieSub string) ([]*FeedGroup, error) {
+	res, err := api.get(
+		"https://weibo.com/ajax/feed/allGroups",
+		req.Header{
+			"Referer": "https://weibo.com/",
+			"Cookie":  fmt.Sprintf("SUB=%s", cookieSub),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboGroups{}
+	if err = api.decode("ajax/feed/allGroups", res, body); err != nil {
+		return nil, err
+	}
+	return body.All(), nil
+}
+
+// GetGroupTimeLine of friend group for current user, need the 'SUB' part of cookie
+func (api *WeiboAPI) GetGroupTimeLine(cookieSub string, gid string, recentBlogId string) (*WeiboTimeLine, error) {
+	res, err := api.get(
+		"https://m.weibo.cn/feed/group",
+		req.QueryParam{
+			"gid":    gid,
+			"max_id": recentBlogId,
+		},
+		req.Header{
+			"Referer":    "https://m.weibo.cn/",
+			"MWeibo-Pwa": "1",
+			"Cookie":     fmt.Sprintf("SUB=%s", cookieSub),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboTimeLine{}
+	if err = api.decode("feed/group", res, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// All groups in the sections, e.g. the default groups and the custom groups
+func (r *WeiboGroups) All() (rt []*FeedGroup) {
+	for _, section := range r.Groups {
+		rt = append(rt, section.Group...)
+	}
+	return rt
+}
+
+func UnmarshalWeiboGroups(data []byte) (WeiboGroups, error) {
+	var r WeiboGroups
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *WeiboGroups) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type WeiboGroups struct {
+	Ok     int64              `json:"ok"`
+	Groups []FeedGroupSection `json:"groups"`
+}
+
+type FeedGroupSection struct {
+	GroupType int64        `json:"group_type"`
+	Title     string       `json:"title"`
+	Group     []*FeedGroup `json:"group"`
+}
+
+type FeedGroup struct {
+	Gid         json.Number `json:"gid"`
+	Title       string      `json:"title"`
+	Type        int64       `json:"type"`
+	Uid         json.Number `json:"uid"`
+	MemberCount *int64      `json:"member_count,omitempty"`
+}
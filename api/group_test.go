@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeiboGroups_All(t *testing.T) {
+	assert := assert.New(t)
+
+	body, err := UnmarshalWeiboGroups([]byte(`{"ok":1,"groups":[
+		{"group_type":0,"title":"默认分组","group":[{"gid":"4000000000000001","title":"全部关注","type":0}]},
+		{"group_type":1,"title":"我的分组","group":[{"gid":4000000000000002,"title":"同学","type":3}]}
+	]}`))
+	assert.Nil(err)
+	groups := body.All()
+	assert.Equal(2, len(groups))
+	assert.Equal("4000000000000001", groups[0].Gid.String())
+	assert.Equal("4000000000000002", groups[1].Gid.String())
+	assert.Equal("同学", groups[1].Title)
+}
+
+func TestUnmarshalWeiboTimeLine(t *testing.T) {
+	assert := assert.New(t)
+
+	body, err := UnmarshalWeiboTimeLine([]byte(`{"ok":1,"data":{
+		"statuses":[{"id":"4823456789012345","text":"hello","user":{"id":1,"screen_name":"a"}}],
+		"max_id":4823456789012344,"max_id_str":"4823456789012344"
+	}}`))
+	assert.Nil(err)
+	assert.Equal(1, len(body.Data.Statuses))
+	assert.Equal(1, len(body.Data.Mblogs))
+	assert.Equal("4823456789012345", *body.Data.Mblogs[0].ID)
+	assert.Equal("a", body.Data.Mblogs[0].User.ScreenName)
+	assert.Equal("4823456789012344", body.Data.MaxIDStr)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/imroc/req"
+)
+
+// GetHotSearch board (热搜榜) in real time, no login needed
+func (api *WeiboAPI) GetHotSearch() (*WeiboHotSearch, error) {
+	res, err := api.get(
+		"https://weibo.com/ajax/side/hotSearch",
+		req.Header{
+			"Referer": "https://weibo.com/",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboHotSearch{}
+	if err = api.decode("ajax/side/hotSearch", res, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Entries of hot search board ranked from 1, the ads are excluded
+func (r *WeiboHotSearch) Entries() (rt []*HotSearchEntry) {
+	for i := range r.Data.Realtime {
+		item := &r.Data.Realtime[i]
+		if item.IsAd != nil && *item.IsAd == 1 {
+			continue
+		}
+		keyword := item.Word
+		if len(keyword) == 0 {
+			keyword = item.Note
+		}
+		rt = append(rt, &HotSearchEntry{
+			Rank:    len(rt) + 1,
+			Keyword: keyword,
+			Heat:    item.Num,
+			Label:   item.LabelName,
+		})
+	}
+	return rt
+}
+
+// HotSearchEntry of hot search board
+type HotSearchEntry struct {
+	Rank    int
+	Keyword string
+	Heat    int64
+	// Label of entry, e.g. '热', '新', '沸' and '爆', empty if none
+	Label string
+}
+
+func UnmarshalWeiboHotSearch(data []byte) (WeiboHotSearch, error) {
+	var r WeiboHotSearch
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *WeiboHotSearch) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type WeiboHotSearch struct {
+	Ok   int64         `json:"ok"`
+	Data HotSearchData `json:"data"`
+}
+
+type HotSearchData struct {
+	Realtime []HotSearchItem `json:"realtime"`
+	Hotgov   *HotSearchItem  `json:"hotgov,omitempty"`
+}
+
+type HotSearchItem struct {
+	Word        string `json:"word"`
+	Note        string `json:"note"`
+	WordScheme  string `json:"word_scheme"`
+	Num         int64  `json:"num"`
+	Rank        int64  `json:"rank"`
+	Realpos     int64  `json:"realpos"`
+	LabelName   string `json:"label_name"`
+	Category    string `json:"category"`
+	Flag        int64  `json:"flag"`
+	OnboardTime int64  `json:"onboard_time"`
+	IsAd        *int64 `json:"is_ad,omitempty"`
+}
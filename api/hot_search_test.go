@@ -0,0 +1,22 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeiboHotSearch_Entries(t *testing.T) {
+	assert := assert.New(t)
+
+	body, err := UnmarshalWeiboHotSearch([]byte(`{"ok":1,"data":{"realtime":[
+		{"word":"天气","num":1234567,"rank":0,"realpos":1,"label_name":"热"},
+		{"word":"广告","num":100,"is_ad":1},
+		{"note":"新闻","num":345,"rank":1,"realpos":2,"label_name":""}
+	]}}`))
+	assert.Nil(err)
+	entries := body.Entries()
+	assert.Equal(2, len(entries))
+	assert.Equal(&HotSearchEntry{1, "天气", 1234567, "热"}, entries[0])
+	assert.Equal(&HotSearchEntry{2, "新闻", 345, ""}, entries[1])
+}
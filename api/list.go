@@ -2,21 +2,30 @@ package api
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/imroc/req"
 )
 
+// GetUserPagesIndex of user posts, page starts from 1
 func (api *WeiboAPI) GetUserPagesIndex(uid string, page int) (*WeiboUserListPageIndex, error) {
 	containerId, err := api.GetContainerId(uid)
 	if err != nil {
 		return nil, err
 	}
-	res, err := req.Get(
+	return api.GetContainerPages(uid, containerId, TAB_WEIBO, page)
+}
+
+// GetContainerPages of user posts in container, e.g. the filter container, page starts from 1,
+// the kind of container (e.g. 'weibo', 'video', 'filter') labels the endpoint in schema drift
+func (api *WeiboAPI) GetContainerPages(uid string, containerId string, kind string, page int) (*WeiboUserListPageIndex, error) {
+	res, err := api.get(
 		"https://m.weibo.cn/api/container/getIndex",
 		req.QueryParam{
 			"type":        "uid",
 			"value":       uid,
 			"containerid": containerId,
+			"page":        page,
 		},
 		req.Header{
 			"Referer":    "https://m.weibo.cn/",
@@ -29,7 +38,7 @@ func (api *WeiboAPI) GetUserPagesIndex(uid string, page int) (*WeiboUserListPage
 
 	body := &WeiboUserListPageIndex{}
 
-	if err = res.ToJSON(body); err != nil {
+	if err = api.decode("container/getIndex?containerid="+kind, res, body); err != nil {
 		return nil, err
 	}
 
@@ -52,10 +61,11 @@ type WeiboUserListPageIndex struct {
 }
 
 type ListPageDat struct {
-	CardlistInfo CardlistInfo `json:"cardlistInfo"`
-	Cards        []Card       `json:"cards"`
-	Scheme       string       `json:"scheme"`
-	ShowAppTips  int64        `json:"showAppTips"`
+	CardlistInfo CardlistInfo   `json:"cardlistInfo"`
+	PageInfo     *TopicPageInfo `json:"pageInfo,omitempty"`
+	Cards        []Card         `json:"cards"`
+	Scheme       string         `json:"scheme"`
+	ShowAppTips  int64          `json:"showAppTips"`
 }
 
 type CardlistInfo struct {
@@ -66,6 +76,20 @@ type CardlistInfo struct {
 	SinceID     int64  `json:"since_id"`
 }
 
+// known card types in card list
+const (
+	CARD_TYPE_MBLOG    = 9
+	CARD_TYPE_USER     = 10
+	CARD_TYPE_GROUP    = 11
+	CARD_TYPE_BANNER   = 22
+	CARD_TYPE_SEARCH   = 31
+	CARD_TYPE_INFO     = 41
+	CARD_TYPE_TITLE    = 42
+	CARD_TYPE_PHOTOS   = 47
+	CARD_TYPE_TIPS     = 58
+	CARD_TYPE_USER_BOX = 161
+)
+
 type Card struct {
 	CardType       int64       `json:"card_type"`
 	CardStyle      *int64      `json:"card_style,omitempty"`
@@ -75,6 +99,7 @@ type Card struct {
 	Itemid         *string     `json:"itemid,omitempty"`
 	Scheme         *string     `json:"scheme,omitempty"`
 	Mblog          *Mblog      `json:"mblog,omitempty"`
+	Pics           []CardPic   `json:"pics,omitempty"`
 }
 
 type CardGroup struct {
@@ -95,6 +120,20 @@ type CardGroup struct {
 	Desc2           *string             `json:"desc2,omitempty"`
 	User            *User               `json:"user,omitempty"`
 	Buttons         []Button            `json:"buttons,omitempty"`
+	Mblog           *Mblog              `json:"mblog,omitempty"`
+	ItemName        *string             `json:"item_name,omitempty"`
+	ItemContent     *string             `json:"item_content,omitempty"`
+	Pics            []CardPic           `json:"pics,omitempty"`
+}
+
+// CardPic of photos card, e.g. in album
+type CardPic struct {
+	PicID     string `json:"pic_id"`
+	PicSmall  string `json:"pic_small"`
+	PicMiddle string `json:"pic_middle"`
+	PicBig    string `json:"pic_big"`
+	// Mblog of photo, nil if the photo is not posted, e.g. avatar
+	Mblog *Mblog `json:"mblog,omitempty"`
 }
 
 type CardGroupActionlog struct {
@@ -186,6 +225,20 @@ type User struct {
 	Like            *bool            `json:"like,omitempty"`
 	LikeMe          *bool            `json:"like_me,omitempty"`
 	Badge           map[string]int64 `json:"badge,omitempty"`
+	// Raw json of user as returned by weibo
+	Raw json.RawMessage `json:"-"`
+}
+
+func (r *User) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	type user User
+	if err := json.Unmarshal(data, (*user)(r)); err != nil {
+		return err
+	}
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
 }
 
 type Mblog struct {
@@ -239,6 +292,72 @@ type Mblog struct {
 	RepostType               *int64           `json:"repost_type,omitempty"`
 	RawText                  *string          `json:"raw_text,omitempty"`
 	Fid                      *int64           `json:"fid,omitempty"`
+	RegionName               *string          `json:"region_name,omitempty"`
+	// pictures in the format of weibo.com, e.g. favorites
+	PicInfos map[string]PicInfo `json:"pic_infos,omitempty"`
+	// FavoritedTime only in favorites, in the same format as 'created_at'
+	FavoritedTime *string `json:"favorited_time,omitempty"`
+	// Raw json of post as returned by weibo
+	Raw json.RawMessage `json:"-"`
+}
+
+func (r *Mblog) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	type mblog Mblog
+	body := struct {
+		*mblog
+		ID json.RawMessage `json:"id,omitempty"`
+	}{mblog: (*mblog)(r)}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	if len(body.ID) > 0 {
+		id := rawId(body.ID)
+		r.ID = &id
+	}
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// rawId of post as string, the id is a number in the format of weibo.com
+func rawId(raw json.RawMessage) string {
+	if string(raw) == "null" {
+		return ""
+	}
+	return strings.Trim(string(raw), `"`)
+}
+
+// AllPics of post, including the pictures in the format of weibo.com
+func (r *Mblog) AllPics() []Pic {
+	if len(r.Pics) > 0 || len(r.PicInfos) == 0 {
+		return r.Pics
+	}
+	rt := make([]Pic, 0, len(r.PicIDS))
+	for _, pid := range r.PicIDS {
+		if info, found := r.PicInfos[pid]; found && len(info.Largest.URL) > 0 {
+			rt = append(rt, Pic{PID: pid, URL: info.Largest.URL})
+		}
+	}
+	return rt
+}
+
+type PicInfo struct {
+	Thumbnail PicInfoImage `json:"thumbnail"`
+	Bmiddle   PicInfoImage `json:"bmiddle"`
+	Large     PicInfoImage `json:"large"`
+	Original  PicInfoImage `json:"original"`
+	Largest   PicInfoImage `json:"largest"`
+	ObjectID  string       `json:"object_id"`
+	PicID     string       `json:"pic_id"`
+	Type      string       `json:"type"`
+}
+
+type PicInfoImage struct {
+	URL    string      `json:"url"`
+	Width  interface{} `json:"width"`
+	Height interface{} `json:"height"`
 }
 
 type AlchemyParams struct {
@@ -261,6 +380,7 @@ type PageInfo struct {
 	PagePic          PagePic    `json:"page_pic"`
 	PageURL          string     `json:"page_url"`
 	PageTitle        string     `json:"page_title"`
+	PageID           *string    `json:"page_id,omitempty"`
 	Content1         string     `json:"content1"`
 	URLOri           *string    `json:"url_ori,omitempty"`
 	ObjectID         *string    `json:"object_id,omitempty"`
@@ -272,6 +392,27 @@ type PageInfo struct {
 	Urls             *Urls      `json:"urls,omitempty"`
 }
 
+// VideoURL of video page in the best quality, empty if the page is not a video
+func (r *PageInfo) VideoURL() string {
+	if r.Type != "video" {
+		return ""
+	}
+	if r.Urls != nil {
+		for _, url := range []string{r.Urls.Mp4720PMp4, r.Urls.Mp4HDMp4, r.Urls.Mp4LdMp4} {
+			if len(url) > 0 {
+				return url
+			}
+		}
+	}
+	if r.MediaInfo != nil {
+		if len(r.MediaInfo.StreamURLHD) > 0 {
+			return r.MediaInfo.StreamURLHD
+		}
+		return r.MediaInfo.StreamURL
+	}
+	return ""
+}
+
 type MediaInfo struct {
 	StreamURL   string  `json:"stream_url"`
 	StreamURLHD string  `json:"stream_url_hd"`
@@ -353,7 +494,35 @@ type RetweetedStatus struct {
 	PicNum                   int64                     `json:"pic_num"`
 	EditConfig               RetweetedStatusEditConfig `json:"edit_config"`
 	PageInfo                 PageInfo                  `json:"page_info"`
+	Pics                     []Pic                     `json:"pics,omitempty"`
 	Bid                      string                    `json:"bid"`
+	Deleted                  *string                   `json:"deleted,omitempty"`
+	EditCount                *int64                    `json:"edit_count,omitempty"`
+	RegionName               *string                   `json:"region_name,omitempty"`
+	// Raw json of post as returned by weibo
+	Raw json.RawMessage `json:"-"`
+}
+
+func (r *RetweetedStatus) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	type retweetedStatus RetweetedStatus
+	body := struct {
+		*retweetedStatus
+		ID json.RawMessage `json:"id"`
+	}{retweetedStatus: (*retweetedStatus)(r)}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	r.ID = rawId(body.ID)
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// IsDeleted check whether the original post has been deleted by author or censored
+func (r *RetweetedStatus) IsDeleted() bool {
+	return (r.Deleted != nil && *r.Deleted == "1") || r.User.ID == 0
 }
 
 type RetweetedStatusEditConfig struct {
@@ -37,3 +37,26 @@ func TestWeiboAPI_GetUserPagesIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestRetweetedStatus_IsDeleted(t *testing.T) {
+	assert := assert.New(t)
+
+	deleted, err := UnmarshalWeiboUserListPageIndex([]byte(`{"ok":1,"data":{"cards":[{"card_type":9,"mblog":{"id":"1","retweeted_status":{"id":"2","text":"抱歉，此微博已被作者删除。","deleted":"1","user":null}}}]}}`))
+	assert.Nil(err)
+	assert.True(deleted.Data.Cards[0].Mblog.RetweetedStatus.IsDeleted())
+
+	normal, err := UnmarshalWeiboUserListPageIndex([]byte(`{"ok":1,"data":{"cards":[{"card_type":9,"mblog":{"id":"1","retweeted_status":{"id":"2","text":"hello","user":{"id":2656274875,"screen_name":"news"}}}}]}}`))
+	assert.Nil(err)
+	assert.False(normal.Data.Cards[0].Mblog.RetweetedStatus.IsDeleted())
+}
+
+func TestMblog_Raw(t *testing.T) {
+	assert := assert.New(t)
+
+	mblog := `{"id":"1","text":"hello","unknown_field":{"a":1},"user":{"id":2656274875,"screen_name":"news"}}`
+	page, err := UnmarshalWeiboUserListPageIndex([]byte(`{"ok":1,"data":{"cards":[{"card_type":9,"mblog":` + mblog + `}]}}`))
+	assert.Nil(err)
+	assert.Equal(mblog, string(page.Data.Cards[0].Mblog.Raw))
+	assert.Equal(`{"id":2656274875,"screen_name":"news"}`, string(page.Data.Cards[0].Mblog.User.Raw))
+	assert.Equal("hello", *page.Data.Cards[0].Mblog.Text)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/imroc/req"
+)
+
+// GetCommentsByMe (我发出的评论) of the account, newest first, page starts from 1, empty if no more.
+// need the 'SUB' part of cookie
+func (api *WeiboAPI) GetCommentsByMe(cookieSub string, page int) (*WeiboComments, error) {
+	body := &WeiboComments{}
+	if err := api.getMessages(cookieSub, "message/myCmt", page, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// GetCommentsToMe (收到的评论) of the account, newest first, page starts from 1, empty if no more.
+// need the 'SUB' part of cookie
+func (api *WeiboAPI) GetCommentsToMe(cookieSub string, page int) (*WeiboComments, error) {
+	body := &WeiboComments{}
+	if err := api.getMessages(cookieSub, "message/cmt", page, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// GetMentions (@我的微博) of the account, newest first, page starts from 1, empty if no more.
+// need the 'SUB' part of cookie
+func (api *WeiboAPI) GetMentions(cookieSub string, page int) (*WeiboMentions, error) {
+	body := &WeiboMentions{}
+	if err := api.getMessages(cookieSub, "message/mentionsAt", page, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (api *WeiboAPI) getMessages(cookieSub string, endpoint string, page int, body interface{}) error {
+	res, err := api.get(
+		"https://m.weibo.cn/"+endpoint,
+		req.QueryParam{
+			"page": page,
+		},
+		req.Header{
+			"Referer":    "https://m.weibo.cn/message",
+			"MWeibo-Pwa": "1",
+			"Cookie":     fmt.Sprintf("SUB=%s", cookieSub),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return api.decode(endpoint, res, body)
+}
+
+// GetLikes (赞过的微博) of user, newest first, page starts from 1, empty if no more.
+// need the 'SUB' part of cookie
+func (api *WeiboAPI) GetLikes(cookieSub string, uid string, page int) (*WeiboLikes, error) {
+	res, err := api.get(
+		"https://weibo.com/ajax/statuses/likelist",
+		req.QueryParam{
+			"uid":  uid,
+			"page": page,
+		},
+		req.Header{
+			"Referer": "https://weibo.com/",
+			"Cookie":  fmt.Sprintf("SUB=%s", cookieSub),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboLikes{}
+	if err = api.decode("ajax/statuses/likelist", res, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func UnmarshalWeiboComments(data []byte) (WeiboComments, error) {
+	var r WeiboComments
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *WeiboComments) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type WeiboComments struct {
+	Ok   int64      `json:"ok"`
+	Data []*Comment `json:"data"`
+}
+
+type Comment struct {
+	ID        json.Number `json:"id"`
+	CreatedAt string      `json:"created_at"`
+	Text      string      `json:"text"`
+	Source    string      `json:"source"`
+	LikeCount *int64      `json:"like_count,omitempty"`
+	User      *User       `json:"user,omitempty"`
+	// Status commented
+	Status *Mblog `json:"status,omitempty"`
+	// ReplyComment replied by the comment, nil if the comment is to the post
+	ReplyComment *Comment `json:"reply_comment,omitempty"`
+}
+
+type WeiboMentions struct {
+	Ok   int64    `json:"ok"`
+	Data []*Mblog `json:"data"`
+}
+
+type WeiboLikes struct {
+	Ok   int64         `json:"ok"`
+	Data WeiboLikeList `json:"data"`
+}
+
+type WeiboLikeList struct {
+	// List of liked posts, in the format of weibo.com
+	List []*Mblog `json:"list"`
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalWeiboComments(t *testing.T) {
+	assert := assert.New(t)
+
+	body, err := UnmarshalWeiboComments([]byte(`{"ok":1,"data":[
+		{"id":"4823456789012345","created_at":"Mon Jan 02 15:04:05 +0800 2023","text":"reply",
+			"user":{"id":1,"screen_name":"me"},
+			"status":{"id":"4823456789000000","text":"post"},
+			"reply_comment":{"id":4823456789000001,"text":"comment"}}
+	]}`))
+	assert.Nil(err)
+	assert.Equal(1, len(body.Data))
+	comment := body.Data[0]
+	assert.Equal("4823456789012345", comment.ID.String())
+	assert.Equal("4823456789000000", *comment.Status.ID)
+	assert.Equal("4823456789000001", comment.ReplyComment.ID.String())
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/imroc/req"
+	"github.com/patrickmn/go-cache"
+)
+
+// POLL_OK_CODE is the business code of a successful poll response
+const POLL_OK_CODE = "100000"
+
+var pollIdFromURL = regexp.MustCompile(`vote_id=([^&#]+)`)
+
+// GetPollId from page info of a post with poll (投票), return empty string if it is not a poll
+func GetPollId(pageInfo *PageInfo) string {
+	if pageInfo == nil {
+		return ""
+	}
+	if matched := pollIdFromURL.FindStringSubmatch(pageInfo.PageURL); len(matched) > 1 {
+		return matched[1]
+	}
+	if pageInfo.Type != "vote" {
+		return ""
+	}
+	if pageInfo.ObjectID != nil {
+		// object id looks like '2297:2019_1234567890'
+		parts := strings.Split(*pageInfo.ObjectID, ":")
+		return parts[len(parts)-1]
+	}
+	return ""
+}
+
+// GetPoll with the votes of options by poll id, the results are final once the poll expires.
+// the poll is cached, so the reposts of the same poll are fetched once
+func (api *WeiboAPI) GetPoll(pollId string) (*WeiboPoll, error) {
+	key := "weibo:poll:" + pollId
+	if value, found := api.cache.Get(key); found {
+		return value.(*WeiboPoll), nil
+	}
+	res, err := api.get(
+		"https://vote.weibo.com/h5/aj/index/info",
+		req.QueryParam{
+			"vote_id": pollId,
+		},
+		req.Header{
+			"Referer": fmt.Sprintf("https://vote.weibo.com/h5/index/index?vote_id=%s", pollId),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboPoll{}
+	if err = api.decode("vote/h5/aj/index/info", res, body); err != nil {
+		return nil, err
+	}
+	if body.Code.String() != POLL_OK_CODE {
+		return nil, fmt.Errorf("fetch poll '%s' failed: %s", pollId, body.Msg)
+	}
+	if body.Data == nil {
+		return nil, errors.New("poll response without data")
+	}
+	expiration := cache.DefaultExpiration
+	if body.Data.VoteInfo.Expired {
+		// the results of expired poll never change
+		expiration = cache.NoExpiration
+	}
+	api.cache.Set(key, body, expiration)
+	return body, nil
+}
+
+func UnmarshalWeiboPoll(data []byte) (WeiboPoll, error) {
+	var r WeiboPoll
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *WeiboPoll) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type WeiboPoll struct {
+	Code json.Number `json:"code"`
+	Msg  string      `json:"msg"`
+	Data *PollData   `json:"data,omitempty"`
+}
+
+type PollData struct {
+	VoteInfo PollInfo `json:"vote_info"`
+}
+
+type PollInfo struct {
+	VoteID  string `json:"vote_id"`
+	Content string `json:"content"`
+	// PartNum count of participants
+	PartNum int64 `json:"part_num"`
+	// Expired poll has the final results
+	Expired    bool         `json:"expired"`
+	OptionList []PollOption `json:"option_list"`
+}
+
+type PollOption struct {
+	Content string `json:"content"`
+	// PartNum count of votes
+	PartNum int64 `json:"part_num"`
+}
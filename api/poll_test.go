@@ -0,0 +1,39 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPollId(t *testing.T) {
+	assert := assert.New(t)
+	objectID := "2297:2019_1234567890"
+	tests := []struct {
+		name     string
+		pageInfo *PageInfo
+		want     string
+	}{
+		{"nil", nil, ""},
+		{"article", &PageInfo{Type: "article", PageURL: "https://card.weibo.com/article/m/show/id/123"}, ""},
+		{"url", &PageInfo{Type: "webpage", PageURL: "https://vote.weibo.com/h5/index/index?vote_id=2019_123&from=1"}, "2019_123"},
+		{"object id", &PageInfo{Type: "vote", ObjectID: &objectID}, "2019_1234567890"},
+	}
+	for _, tt := range tests {
+		assert.Equal(tt.want, GetPollId(tt.pageInfo), tt.name)
+	}
+}
+
+func TestUnmarshalWeiboPoll(t *testing.T) {
+	assert := assert.New(t)
+
+	body, err := UnmarshalWeiboPoll([]byte(`{"code":100000,"msg":"","data":{"vote_info":{
+		"vote_id":"2019_123","content":"午饭吃什么","part_num":30,"expired":true,
+		"option_list":[{"content":"米饭","part_num":20},{"content":"面条","part_num":10}]
+	}}}`))
+	assert.Nil(err)
+	assert.Equal(POLL_OK_CODE, body.Code.String())
+	assert.Equal("午饭吃什么", body.Data.VoteInfo.Content)
+	assert.Equal(2, len(body.Data.VoteInfo.OptionList))
+	assert.Equal(int64(20), body.Data.VoteInfo.OptionList[0].PartNum)
+}
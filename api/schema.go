@@ -0,0 +1,197 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/imroc/req"
+)
+
+// EndpointDrift of weibo response schema, compared with the structures in this package
+type EndpointDrift struct {
+	Endpoint string
+	// count of checked responses
+	Responses int
+	// json path of unknown field -> count
+	Unknown map[string]int
+	// json path with changed type (e.g. 'data.width: string -> number') -> count
+	Changed map[string]int
+}
+
+// HasDrift in checked responses
+func (d *EndpointDrift) HasDrift() bool {
+	return len(d.Unknown) > 0 || len(d.Changed) > 0
+}
+
+type schemaDrift struct {
+	mu        sync.Mutex
+	endpoints map[string]*EndpointDrift
+}
+
+// EnableStrictDecode to check each response against the structures, the result could be got by 'GetSchemaDrift'
+func (api *WeiboAPI) EnableStrictDecode() {
+	api.drift = &schemaDrift{endpoints: map[string]*EndpointDrift{}}
+}
+
+// GetSchemaDrift of all endpoints called after strict decode enabled, sorted by endpoint
+func (api *WeiboAPI) GetSchemaDrift() (rt []*EndpointDrift) {
+	if api.drift == nil {
+		return nil
+	}
+	api.drift.mu.Lock()
+	defer api.drift.mu.Unlock()
+	for _, d := range api.drift.endpoints {
+		rt = append(rt, d)
+	}
+	sort.Slice(rt, func(i, j int) bool { return rt[i].Endpoint < rt[j].Endpoint })
+	return rt
+}
+
+// decode response body of endpoint, fail on unexpected status code, and record schema drift in strict mode
+func (api *WeiboAPI) decode(endpoint string, res *req.Resp, body interface{}) error {
+	if code := res.Response().StatusCode; code != http.StatusOK {
+		return &StatusError{endpoint, code}
+	}
+	if err := res.ToJSON(body); err != nil {
+		return err
+	}
+	if api.drift != nil {
+		if err := api.drift.check(endpoint, res.Bytes(), body); err != nil {
+			return fmt.Errorf("check schema of %s failed: %w", endpoint, err)
+		}
+	}
+	return nil
+}
+
+func (s *schemaDrift) check(endpoint string, data []byte, body interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, found := s.endpoints[endpoint]
+	if !found {
+		d = &EndpointDrift{Endpoint: endpoint, Unknown: map[string]int{}, Changed: map[string]int{}}
+		s.endpoints[endpoint] = d
+	}
+	d.Responses++
+	return CheckSchema(data, reflect.TypeOf(body), d)
+}
+
+// CheckSchema of json data against type, unknown and changed fields are counted into drift
+func CheckSchema(data []byte, t reflect.Type, drift *EndpointDrift) error {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	walkSchema("", value, t, drift)
+	return nil
+}
+
+func walkSchema(path string, value interface{}, t reflect.Type, drift *EndpointDrift) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if value == nil || t.Kind() == reflect.Interface {
+		return
+	}
+	if t == reflect.TypeOf(json.RawMessage{}) {
+		return
+	}
+	// number could be quoted in json
+	if got := jsonKind(value); t == reflect.TypeOf(json.Number("")) && (got == "string" || got == "integer" || got == "number") {
+		return
+	}
+	want, got := schemaKind(t), jsonKind(value)
+	if want != got && !(want == "number" && got == "integer") {
+		drift.Changed[fmt.Sprintf("%s: %s -> %s", path, want, got)]++
+		return
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if t.Kind() == reflect.Map {
+			for key, item := range v {
+				walkSchema(joinPath(path, key), item, t.Elem(), drift)
+			}
+			return
+		}
+		fields := jsonFields(t)
+		for key, item := range v {
+			field, found := fields[key]
+			if !found {
+				drift.Unknown[joinPath(path, key)]++
+				continue
+			}
+			walkSchema(joinPath(path, key), item, field, drift)
+		}
+	case []interface{}:
+		for _, item := range v {
+			walkSchema(path+"[]", item, t.Elem(), drift)
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonFields of struct, json name -> type
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	rt := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		rt[name] = field.Type
+	}
+	return rt
+}
+
+func schemaKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func jsonKind(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []byte(`{"ok":1,"data":{"cards":[
+		{"card_type":9,"mblog":{"id":"1","new_field":1,"pics":[{"pid":"a","large":{"geo":{"width":1080,"height":"720"}}}]}},
+		{"card_type":9,"mblog":{"id":"2","new_field":2,"user":{"id":"3","desc2":{"a":1}}}}
+	]}}`)
+	drift := &EndpointDrift{Unknown: map[string]int{}, Changed: map[string]int{}}
+	assert.Nil(CheckSchema(data, reflect.TypeOf(&WeiboUserListPageIndex{}), drift))
+	assert.True(drift.HasDrift())
+	assert.Equal(map[string]int{"data.cards[].mblog.new_field": 2}, drift.Unknown)
+	assert.Equal(map[string]int{
+		"data.cards[].mblog.pics[].large.geo.width: string -> integer": 1,
+		"data.cards[].mblog.user.id: integer -> string":                1,
+	}, drift.Changed)
+}
+
+func TestSchemaDrift_Check(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &schemaDrift{endpoints: map[string]*EndpointDrift{}}
+	assert.Nil(s.check("container/getIndex?containerid=video", []byte(`{"ok":1}`), &WeiboUserListPageIndex{}))
+	assert.NotNil(s.check("container/getIndex?containerid=video", []byte(`{"ok":`), &WeiboUserListPageIndex{}))
+	assert.Equal(2, s.endpoints["container/getIndex?containerid=video"].Responses)
+}
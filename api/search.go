@@ -0,0 +1,69 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/imroc/req"
+)
+
+// search types, as the 'type' of search container id
+const (
+	SEARCH_TYPE_USER     = "3"
+	SEARCH_TYPE_HOT      = "60"
+	SEARCH_TYPE_REALTIME = "61"
+)
+
+// SearchRealtime posts of keyword, newest first, page starts from 1
+func (api *WeiboAPI) SearchRealtime(keyword string, page int) (*WeiboUserListPageIndex, error) {
+	return api.Search(SEARCH_TYPE_REALTIME, keyword, page)
+}
+
+// SearchHot posts of keyword, ordered by popularity, page starts from 1
+func (api *WeiboAPI) SearchHot(keyword string, page int) (*WeiboUserListPageIndex, error) {
+	return api.Search(SEARCH_TYPE_HOT, keyword, page)
+}
+
+// SearchUsers by keyword, page starts from 1, empty if no more
+func (api *WeiboAPI) SearchUsers(keyword string, page int) ([]*User, error) {
+	body, err := api.Search(SEARCH_TYPE_USER, keyword, page)
+	if err != nil {
+		return nil, err
+	}
+	// weibo responds 'ok: 0' after the last page
+	if body.Ok != 1 {
+		return nil, nil
+	}
+	return body.Users(), nil
+}
+
+// Search container of keyword with search type, page starts from 1
+func (api *WeiboAPI) Search(searchType string, keyword string, page int) (*WeiboUserListPageIndex, error) {
+	containerId := SearchContainerId(searchType, keyword)
+	res, err := api.get(
+		"https://m.weibo.cn/api/container/getIndex",
+		req.QueryParam{
+			"containerid": containerId,
+			"page_type":   "searchall",
+			"page":        page,
+		},
+		req.Header{
+			"Referer":    "https://m.weibo.cn/search?containerid=" + url.QueryEscape(containerId),
+			"MWeibo-Pwa": "1",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboUserListPageIndex{}
+	if err = api.decode("container/getIndex?containerid=search", res, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// SearchContainerId of keyword with search type, the keyword is escaped so that
+// '&' or '=' in it does not break the container id
+func SearchContainerId(searchType string, keyword string) string {
+	return fmt.Sprintf("100103type=%s&q=%s&t=0", searchType, url.QueryEscape(keyword))
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchContainerId(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("100103type=61&q=%E5%A4%A9%E6%B0%94&t=0", SearchContainerId(SEARCH_TYPE_REALTIME, "天气"))
+	assert.Equal("100103type=3&q=%23%E8%AF%9D%E9%A2%98%23&t=0", SearchContainerId(SEARCH_TYPE_USER, "#话题#"))
+	assert.Equal("100103type=61&q=a%26t%3D1+b&t=0", SearchContainerId(SEARCH_TYPE_REALTIME, "a&t=1 b"))
+}
+
+func TestSearchUsers(t *testing.T) {
+	assert := assert.New(t)
+
+	page, err := UnmarshalWeiboUserListPageIndex([]byte(`{"ok":1,"data":{"cards":[
+		{"card_type":11,"card_group":[
+			{"card_type":10,"user":{"id":1,"screen_name":"a"}},
+			{"card_type":10,"user":{"id":2,"screen_name":"b"}}
+		]},
+		{"card_type":11,"card_group":[
+			{"card_type":9,"mblog":{"id":"3"}}
+		]}
+	]}}`))
+	assert.Nil(err)
+	users := page.Users()
+	assert.Equal(2, len(users))
+	assert.Equal("1", users[0].Uid())
+	assert.Equal("b", users[1].ScreenName)
+}
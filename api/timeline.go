@@ -10,7 +10,7 @@ import (
 
 // GetTimeLine for current user, need the 'SUB' part of cookie
 func (api *WeiboAPI) GetTimeLine(cookieSub string, recentBlogId string) (*WeiboTimeLine, error) {
-	res, err := req.Get(
+	res, err := api.get(
 		"https://m.weibo.cn/feed/friends",
 		req.QueryParam{
 			"max_id": recentBlogId,
@@ -25,7 +25,7 @@ func (api *WeiboAPI) GetTimeLine(cookieSub string, recentBlogId string) (*WeiboT
 		return nil, err
 	}
 	body := &WeiboTimeLine{}
-	if err = res.ToJSON(body); err != nil {
+	if err = api.decode("feed/friends", res, body); err != nil {
 		return nil, err
 	}
 	return body, nil
@@ -64,6 +64,26 @@ type TimeLineData struct {
 	MaxID             int64         `json:"max_id"`
 	MaxIDStr          string        `json:"max_id_str"`
 	HasUnread         int64         `json:"has_unread"`
+	// Mblogs of statuses, in the same format as the posts in user pages
+	Mblogs []*Mblog `json:"-"`
+}
+
+func (r *TimeLineData) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	type timeLineData TimeLineData
+	if err := json.Unmarshal(data, (*timeLineData)(r)); err != nil {
+		return err
+	}
+	mblogs := struct {
+		Statuses []*Mblog `json:"statuses"`
+	}{}
+	if err := json.Unmarshal(data, &mblogs); err != nil {
+		return err
+	}
+	r.Mblogs = mblogs.Statuses
+	return nil
 }
 
 type Status struct {
@@ -124,6 +144,7 @@ type Status struct {
 	RawText                  *string                `json:"raw_text,omitempty"`
 	SafeTags                 *int64                 `json:"safe_tags,omitempty"`
 	Fid                      *int64                 `json:"fid,omitempty"`
+	RegionName               *string                `json:"region_name,omitempty"`
 }
 
 type DarwinTag struct {
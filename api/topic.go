@@ -0,0 +1,130 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/imroc/req"
+)
+
+// prefix of super topic (超话) container id, followed by the hash of super topic
+const SUPER_TOPIC_CONTAINER_PREFIX = "100808"
+
+var (
+	topicReadPattern       = regexp.MustCompile(`阅读\s*([\d.]+[万亿]?)`)
+	topicDiscussionPattern = regexp.MustCompile(`(?:讨论|帖子)\s*([\d.]+[万亿]?)`)
+	topicHostPattern       = regexp.MustCompile(`主持人\s*[:：]\s*@?(\S+)`)
+)
+
+// TopicContainerId of hashtag topic, the topic could be with or without '#'
+func TopicContainerId(topic string) string {
+	return fmt.Sprintf("231522type=1&t=10&q=#%s#", strings.Trim(topic, "#"))
+}
+
+// SuperTopicContainerId of super topic, the id could be the container id or the hash of super topic,
+// e.g. the 'topic_id' of status
+func SuperTopicContainerId(id string) string {
+	// the 'topic_id' of status is prefixed by the type, e.g. '1022:100808...'
+	if i := strings.LastIndex(id, ":"); i >= 0 {
+		id = id[i+1:]
+	}
+	id = strings.TrimSuffix(id, "_-_feed")
+	if strings.HasPrefix(id, SUPER_TOPIC_CONTAINER_PREFIX) {
+		return id
+	}
+	return SUPER_TOPIC_CONTAINER_PREFIX + id
+}
+
+// IsSuperTopic container id
+func IsSuperTopic(containerId string) bool {
+	return strings.HasPrefix(containerId, SUPER_TOPIC_CONTAINER_PREFIX)
+}
+
+// GetTopicPages of hashtag topic or super topic container, page starts from 1.
+// the feed of super topic is paged by 'since_id' of previous page, empty for the first page
+func (api *WeiboAPI) GetTopicPages(containerId string, sinceId string, page int) (*WeiboUserListPageIndex, error) {
+	query := req.QueryParam{
+		"containerid": containerId,
+		"page":        page,
+	}
+	if IsSuperTopic(containerId) {
+		query["containerid"] = containerId + "_-_feed"
+		if len(sinceId) > 0 {
+			query["since_id"] = sinceId
+		}
+	}
+	res, err := api.get(
+		"https://m.weibo.cn/api/container/getIndex",
+		query,
+		req.Header{
+			"Referer":    "https://m.weibo.cn/",
+			"MWeibo-Pwa": "1",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	body := &WeiboUserListPageIndex{}
+	if err = api.decode("container/getIndex?containerid=topic", res, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// header of topic page, only in the first page
+type TopicPageInfo struct {
+	Containerid  string   `json:"containerid"`
+	PageTypeName string   `json:"page_type_name"`
+	PageTitle    string   `json:"page_title"`
+	TitleTop     string   `json:"title_top"`
+	Nick         string   `json:"nick"`
+	Portrait     string   `json:"portrait"`
+	Desc         string   `json:"desc"`
+	DescMore     []string `json:"desc_more"`
+	DetailDesc   string   `json:"detail_desc"`
+}
+
+// ReadCount of topic, 0 if unknown
+func (r *TopicPageInfo) ReadCount() int64 {
+	return ParseCount(r.match(topicReadPattern))
+}
+
+// DiscussionCount of topic, or count of posts of super topic, 0 if unknown
+func (r *TopicPageInfo) DiscussionCount() int64 {
+	return ParseCount(r.match(topicDiscussionPattern))
+}
+
+// Host (主持人) of topic, empty if unknown
+func (r *TopicPageInfo) Host() string {
+	return r.match(topicHostPattern)
+}
+
+// match the first group of pattern in descriptions
+func (r *TopicPageInfo) match(pattern *regexp.Regexp) string {
+	for _, desc := range append([]string{r.Desc, r.DetailDesc}, r.DescMore...) {
+		if matched := pattern.FindStringSubmatch(desc); matched != nil {
+			return matched[1]
+		}
+	}
+	return ""
+}
+
+// ParseCount displayed by weibo, e.g. '1.2万' or '3亿', 0 if invalid
+func ParseCount(s string) int64 {
+	multiple := 1.0
+	switch {
+	case strings.HasSuffix(s, "万"):
+		multiple = 1e4
+		s = strings.TrimSuffix(s, "万")
+	case strings.HasSuffix(s, "亿"):
+		multiple = 1e8
+		s = strings.TrimSuffix(s, "亿")
+	}
+	count, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(count*multiple + 0.5)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCount(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]int64{
+		"123":   123,
+		"1.2万":  12000,
+		"3亿":    300000000,
+		"5.67万": 56700,
+		"":      0,
+		"-":     0,
+	}
+	for s, expected := range tests {
+		assert.Equal(expected, ParseCount(s), s)
+	}
+}
+
+func TestTopicPageInfo(t *testing.T) {
+	assert := assert.New(t)
+	info := &TopicPageInfo{
+		Desc:     "主持人：@新闻",
+		DescMore: []string{"阅读1.3亿 讨论5.6万 详情>"},
+	}
+	assert.Equal(int64(130000000), info.ReadCount())
+	assert.Equal(int64(56000), info.DiscussionCount())
+	assert.Equal("新闻", info.Host())
+
+	superTopic := &TopicPageInfo{DescMore: []string{"帖子2.5万  粉丝1.3万"}}
+	assert.Equal(int64(0), superTopic.ReadCount())
+	assert.Equal(int64(25000), superTopic.DiscussionCount())
+	assert.Equal("", superTopic.Host())
+}
+
+func TestTopicContainerId(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("231522type=1&t=10&q=#天气#", TopicContainerId("#天气#"))
+	assert.Equal("231522type=1&t=10&q=#天气#", TopicContainerId("天气"))
+	assert.Equal("100808abc", SuperTopicContainerId("1022:100808abc"))
+	assert.Equal("100808abc", SuperTopicContainerId("abc"))
+	assert.Equal("100808abc", SuperTopicContainerId("100808abc_-_feed"))
+	assert.True(IsSuperTopic("100808abc"))
+	assert.False(IsSuperTopic(TopicContainerId("天气")))
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr"`
+	Weight    int            `xml:"weight,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// WriteGEXF (1.3) of graph, the native format of Gephi
+func (g *Graph) WriteGEXF(w io.Writer) error {
+	doc := gexf{
+		Xmlns:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes: []gexfAttributes{
+				{"node", []gexfAttribute{
+					{"followers_count", "followers_count", "long"},
+					{"verified", "verified", "boolean"},
+					{"mentioned", "mentioned", "boolean"},
+				}},
+				{"edge", []gexfAttribute{
+					{"type", "type", "string"},
+				}},
+			},
+		},
+	}
+	for _, node := range g.Nodes() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:    string(node.ID),
+			Label: node.ScreenName,
+			AttValues: []gexfAttValue{
+				{"followers_count", strconv.FormatInt(node.FollowersCount, 10)},
+				{"verified", strconv.FormatBool(node.Verified)},
+				{"mentioned", strconv.FormatBool(node.Mentioned)},
+			},
+		})
+	}
+	for i, edge := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:        strconv.Itoa(i),
+			Source:    string(edge.Source),
+			Target:    string(edge.Target),
+			Label:     edge.Type,
+			Weight:    edge.Weight,
+			AttValues: []gexfAttValue{{"type", edge.Type}},
+		})
+	}
+	return writeXML(w, doc)
+}
@@ -0,0 +1,229 @@
+package export
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/provision"
+)
+
+// edge types of social graph
+const (
+	EDGE_FOLLOW  = "follow"
+	EDGE_MENTION = "mention"
+	EDGE_REPOST  = "repost"
+	EDGE_COMMENT = "comment"
+)
+
+// KEY_WEIBO_MENTIONED_USER_TYPE of the users only mentioned by screen name, whose uid is unknown
+const KEY_WEIBO_MENTIONED_USER_TYPE = "WeiboMentionedUser"
+
+// mentions in markdown content, e.g. '[@name](https://m.weibo.cn/n/name)' or plain '@name',
+// the '@' must not follow a word character so that emails like 'foo@bar.com' are skipped
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_\-])@([\p{L}\p{N}_\-]{1,30})`)
+
+// Node of user
+type Node struct {
+	ID             model.ID
+	ScreenName     string
+	FollowersCount int64
+	Verified       bool
+	// Mentioned only, the user is never archived and identified by the screen name
+	Mentioned bool
+}
+
+// Edge between users, directed from source to target
+type Edge struct {
+	Source model.ID
+	Target model.ID
+	Type   string
+	// Weight is the count of interactions
+	Weight int
+}
+
+type edgeKey struct {
+	source, target model.ID
+	edgeType       string
+}
+
+// pending edge to the author of an article, resolved once all articles are added
+type articleEdge struct {
+	source    model.ID
+	articleID model.ID
+	edgeType  string
+}
+
+// Graph of weibo users, built from archived articles
+type Graph struct {
+	// PublicOnly excludes the non-public articles, e.g. friends-only or paid
+	// posts, so that the graph could be shared
+	PublicOnly bool
+	nodes      map[model.ID]*Node
+	edges      map[edgeKey]*Edge
+	// article id -> author id
+	articleAuthors map[model.ID]model.ID
+	articleEdges   []articleEdge
+	// mentioned screen name -> source authors
+	mentions map[string][]model.ID
+}
+
+// NewGraph to add archived articles
+func NewGraph() *Graph {
+	return &Graph{
+		nodes:          map[model.ID]*Node{},
+		edges:          map[edgeKey]*Edge{},
+		articleAuthors: map[model.ID]model.ID{},
+		mentions:       map[string][]model.ID{},
+	}
+}
+
+// AddArticle archived by weibo services
+func (g *Graph) AddArticle(article *model.Article) {
+	if article.Author == nil {
+		return
+	}
+	if g.PublicOnly && !provision.IsPublic(article) {
+		return
+	}
+	author := g.addAuthor(article.Author)
+	g.articleAuthors[article.ID] = author.ID
+	for _, ref := range article.References {
+		target := model.ID(ref.ReferenceId)
+		switch ref.Type {
+		case provision.RefTypeFollowing:
+			g.addEdge(author.ID, target, EDGE_FOLLOW)
+		case provision.RefTypeFollower:
+			g.addEdge(target, author.ID, EDGE_FOLLOW)
+		case provision.RefTypeRetweet:
+			g.articleEdges = append(g.articleEdges, articleEdge{author.ID, target, EDGE_REPOST})
+		case provision.RefTypeComment:
+			g.articleEdges = append(g.articleEdges, articleEdge{author.ID, target, EDGE_COMMENT})
+		}
+	}
+	// the user record of social graph service describes the user itself
+	if article.Content != nil && article.Type != provision.KEY_WEIBO_USER_TYPE {
+		for _, matched := range mentionPattern.FindAllStringSubmatch(*article.Content, -1) {
+			g.mentions[matched[1]] = append(g.mentions[matched[1]], author.ID)
+		}
+	}
+}
+
+func (g *Graph) addAuthor(author *model.Author) *Node {
+	node, found := g.nodes[author.ID]
+	if !found {
+		node = &Node{ID: author.ID}
+		g.nodes[author.ID] = node
+	}
+	// later articles may have more recent user info
+	if len(author.FullName) > 0 {
+		node.ScreenName = author.FullName
+	}
+	if count, ok := author.ExtAttributes[provision.EXT_USER_FOLLOWERS_COUNT].(int64); ok {
+		node.FollowersCount = count
+	}
+	if verified, ok := author.ExtAttributes[provision.EXT_USER_VERIFIED].(bool); ok {
+		node.Verified = verified
+	}
+	return node
+}
+
+func (g *Graph) addEdge(source, target model.ID, edgeType string) {
+	if source == target {
+		return
+	}
+	if _, found := g.nodes[target]; !found {
+		g.nodes[target] = &Node{ID: target}
+	}
+	key := edgeKey{source, target, edgeType}
+	if edge, found := g.edges[key]; found {
+		edge.Weight++
+		return
+	}
+	g.edges[key] = &Edge{source, target, edgeType, 1}
+}
+
+// resolve pending edges, the referenced articles and mentioned users may be added later
+func (g *Graph) resolve() {
+	for _, pending := range g.articleEdges {
+		if target, found := g.articleAuthors[pending.articleID]; found {
+			g.addEdge(pending.source, target, pending.edgeType)
+		}
+	}
+	g.articleEdges = nil
+	byName := map[string]model.ID{}
+	for _, node := range g.nodes {
+		if len(node.ScreenName) > 0 && !node.Mentioned {
+			byName[node.ScreenName] = node.ID
+		}
+	}
+	// the users mentioned before are archived later
+	for _, node := range g.nodes {
+		if target, found := byName[node.ScreenName]; found && node.Mentioned {
+			g.mergeMentioned(node.ID, target)
+		}
+	}
+	for name, sources := range g.mentions {
+		target, found := byName[name]
+		if !found {
+			target = model.CreateID(KEY_WEIBO_MENTIONED_USER_TYPE, name)
+			if _, found := g.nodes[target]; !found {
+				g.nodes[target] = &Node{ID: target, ScreenName: name, Mentioned: true}
+			}
+		}
+		for _, source := range sources {
+			g.addEdge(source, target, EDGE_MENTION)
+		}
+	}
+	g.mentions = map[string][]model.ID{}
+}
+
+// mergeMentioned user into the archived one of the same screen name
+func (g *Graph) mergeMentioned(mentioned, target model.ID) {
+	for key, edge := range g.edges {
+		if key.target != mentioned {
+			continue
+		}
+		delete(g.edges, key)
+		merged := edgeKey{key.source, target, key.edgeType}
+		if existing, found := g.edges[merged]; found {
+			existing.Weight += edge.Weight
+			continue
+		}
+		if key.source != target {
+			edge.Target = target
+			g.edges[merged] = edge
+		}
+	}
+	delete(g.nodes, mentioned)
+}
+
+// Nodes sorted by id
+func (g *Graph) Nodes() []*Node {
+	g.resolve()
+	rt := make([]*Node, 0, len(g.nodes))
+	for _, node := range g.nodes {
+		rt = append(rt, node)
+	}
+	sort.Slice(rt, func(i, j int) bool { return rt[i].ID < rt[j].ID })
+	return rt
+}
+
+// Edges sorted by source, target and type
+func (g *Graph) Edges() []*Edge {
+	g.resolve()
+	rt := make([]*Edge, 0, len(g.edges))
+	for _, edge := range g.edges {
+		rt = append(rt, edge)
+	}
+	sort.Slice(rt, func(i, j int) bool {
+		if rt[i].Source != rt[j].Source {
+			return rt[i].Source < rt[j].Source
+		}
+		if rt[i].Target != rt[j].Target {
+			return rt[i].Target < rt[j].Target
+		}
+		return rt[i].Type < rt[j].Type
+	})
+	return rt
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/provision"
+	"github.com/stretchr/testify/assert"
+)
+
+func createAuthor(id int64, name string, followers int64) *model.Author {
+	return &model.Author{
+		ID:       model.CreateID(provision.KEY_WEIBO_USER_TYPE, id),
+		FullName: name,
+		ExtAttributes: map[string]interface{}{
+			provision.EXT_USER_FOLLOWERS_COUNT: followers,
+			provision.EXT_USER_VERIFIED:        true,
+		},
+	}
+}
+
+func createGraph() *Graph {
+	alice := createAuthor(1, "alice", 10)
+	bob := createAuthor(2, "bob", 20)
+	repost := "转发微博 [@carol](https://m.weibo.cn/n/carol)"
+	original := model.CreateID(provision.KEY_WEIBO_ARTICLE_TYPE, "2")
+
+	g := NewGraph()
+	g.AddArticle(&model.Article{
+		ID:     model.CreateID(provision.KEY_WEIBO_SOCIAL_GRAPH_TYPE, "1"),
+		Type:   provision.KEY_WEIBO_SOCIAL_GRAPH_TYPE,
+		Author: alice,
+		References: []*model.Reference{
+			{Type: provision.RefTypeFollowing, ReferenceId: string(bob.ID)},
+		},
+	})
+	g.AddArticle(&model.Article{
+		ID:      model.CreateID(provision.KEY_WEIBO_ARTICLE_TYPE, "1"),
+		Author:  alice,
+		Content: &repost,
+		References: []*model.Reference{
+			{Type: provision.RefTypeRetweet, ReferenceId: string(original)},
+		},
+	})
+	// the original is emitted after the repost
+	g.AddArticle(&model.Article{ID: original, Author: bob})
+	return g
+}
+
+func TestGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	g := createGraph()
+	nodes := g.Nodes()
+	assert.Len(nodes, 3)
+	edges := map[string]int{}
+	for _, edge := range g.Edges() {
+		edges[edge.Type]++
+	}
+	assert.Equal(map[string]int{EDGE_FOLLOW: 1, EDGE_REPOST: 1, EDGE_MENTION: 1}, edges)
+}
+
+func TestGraph_Mentions(t *testing.T) {
+	assert := assert.New(t)
+
+	content := "@alice 你好 [@bob](https://m.weibo.cn/n/bob), mail me at foo@bar.com (@carol)"
+	g := NewGraph()
+	g.AddArticle(&model.Article{
+		ID:      model.CreateID(provision.KEY_WEIBO_ARTICLE_TYPE, "1"),
+		Author:  createAuthor(1, "dave", 10),
+		Content: &content,
+	})
+	var mentioned []string
+	names := map[model.ID]string{}
+	for _, node := range g.Nodes() {
+		names[node.ID] = node.ScreenName
+	}
+	for _, edge := range g.Edges() {
+		mentioned = append(mentioned, names[edge.Target])
+	}
+	assert.ElementsMatch([]string{"alice", "bob", "carol"}, mentioned)
+}
+
+func TestGraph_MentionedLater(t *testing.T) {
+	assert := assert.New(t)
+
+	alice, dave := createAuthor(1, "alice", 10), createAuthor(4, "dave", 10)
+	mention := "@alice @carol"
+	g := NewGraph()
+	g.AddArticle(&model.Article{ID: model.CreateID(provision.KEY_WEIBO_ARTICLE_TYPE, "1"), Author: dave, Content: &mention})
+	// alice is not archived yet
+	carol := model.CreateID(KEY_WEIBO_MENTIONED_USER_TYPE, "carol")
+	mentioned := model.CreateID(KEY_WEIBO_MENTIONED_USER_TYPE, "alice")
+	assert.Len(g.Nodes(), 3)
+	assert.True(g.nodes[mentioned].Mentioned)
+
+	g.AddArticle(&model.Article{ID: model.CreateID(provision.KEY_WEIBO_ARTICLE_TYPE, "2"), Author: dave, Content: &mention})
+	g.AddArticle(&model.Article{ID: model.CreateID(provision.KEY_WEIBO_ARTICLE_TYPE, "3"), Author: alice})
+	// the mentioned alice is merged into the archived one
+	var ids []model.ID
+	for _, node := range g.Nodes() {
+		ids = append(ids, node.ID)
+	}
+	assert.ElementsMatch([]model.ID{alice.ID, dave.ID, carol}, ids)
+	assert.False(g.nodes[alice.ID].Mentioned)
+	assert.True(g.nodes[carol].Mentioned)
+	weights := map[model.ID]int{}
+	for _, edge := range g.Edges() {
+		assert.Equal(dave.ID, edge.Source)
+		weights[edge.Target] = edge.Weight
+	}
+	assert.Equal(map[model.ID]int{alice.ID: 2, carol: 2}, weights)
+}
+
+func TestGraph_Write(t *testing.T) {
+	assert := assert.New(t)
+
+	graphML := &bytes.Buffer{}
+	assert.Nil(createGraph().WriteGraphML(graphML))
+	assert.Contains(graphML.String(), `<graph id="weibo" edgedefault="directed">`)
+	assert.Contains(graphML.String(), `<data key="screen_name">alice</data>`)
+
+	gexf := &bytes.Buffer{}
+	assert.Nil(createGraph().WriteGEXF(gexf))
+	assert.Contains(gexf.String(), `<gexf xmlns="http://gexf.net/1.3" version="1.3">`)
+	assert.Contains(gexf.String(), `label="repost"`)
+}
+
+func TestGraph_PublicOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	alice := createAuthor(1, "alice", 10)
+	mention := "[@bob](https://m.weibo.cn/n/bob)"
+	private := &model.Article{
+		ID:            model.CreateID(provision.KEY_WEIBO_ARTICLE_TYPE, "1"),
+		Author:        alice,
+		Content:       &mention,
+		ExtAttributes: map[string]interface{}{provision.EXT_ACCESS_LEVEL: provision.ACCESS_FRIENDS},
+	}
+
+	g := NewGraph()
+	g.AddArticle(private)
+	assert.Len(g.Edges(), 1)
+
+	g = NewGraph()
+	g.PublicOnly = true
+	g.AddArticle(private)
+	// the audience of article without access level is unknown
+	g.AddArticle(&model.Article{
+		ID:      model.CreateID(provision.KEY_WEIBO_ARTICLE_TYPE, "2"),
+		Author:  alice,
+		Content: &mention,
+	})
+	assert.Len(g.Nodes(), 0)
+	assert.Len(g.Edges(), 0)
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML of graph, could be opened by Gephi, yEd and networkx
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{"screen_name", "node", "screen_name", "string"},
+			{"followers_count", "node", "followers_count", "long"},
+			{"verified", "node", "verified", "boolean"},
+			{"mentioned", "node", "mentioned", "boolean"},
+			{"type", "edge", "type", "string"},
+			{"weight", "edge", "weight", "double"},
+		},
+		Graph: graphMLGraph{ID: "weibo", EdgeDefault: "directed"},
+	}
+	for _, node := range g.Nodes() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: string(node.ID),
+			Data: []graphMLData{
+				{"screen_name", node.ScreenName},
+				{"followers_count", strconv.FormatInt(node.FollowersCount, 10)},
+				{"verified", strconv.FormatBool(node.Verified)},
+				{"mentioned", strconv.FormatBool(node.Mentioned)},
+			},
+		})
+	}
+	for i, edge := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: string(edge.Source),
+			Target: string(edge.Target),
+			Data: []graphMLData{
+				{"type", edge.Type},
+				{"weight", strconv.Itoa(edge.Weight)},
+			},
+		})
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
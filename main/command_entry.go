@@ -1,9 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"io"
+	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/ArchiveLife/core/model"
 	"github.com/urfave/cli"
 )
 
@@ -16,6 +22,19 @@ var commandEntry = cli.Command{
 			Name:  "port",
 			Value: ":8888",
 		},
+		cli.DurationFlag{
+			Name:  "hot-search-interval",
+			Usage: "interval of hot search snapshots, e.g. '1h', disabled by default",
+		},
+		cli.IntFlag{
+			Name:  "hot-search-top",
+			Usage: "fetch the hot posts behind the top N entries of each snapshot",
+		},
+		cli.StringFlag{
+			Name:  "hot-search-output",
+			Value: "hot_search",
+			Usage: "directory of hot search snapshots, one JSON lines file per day",
+		},
 	},
 }
 
@@ -23,6 +42,10 @@ func entry(c *cli.Context) error {
 
 	port := c.String("port")
 
+	if interval := c.Duration("hot-search-interval"); interval > 0 {
+		go snapshotHotSearch(interval, c.Int("hot-search-top"), c.String("hot-search-output"))
+	}
+
 	http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
 		io.WriteString(w, "Hello, world!\n")
 	})
@@ -30,3 +53,36 @@ func entry(c *cli.Context) error {
 	return http.ListenAndServe(port, nil)
 
 }
+
+// snapshotHotSearch periodically, the failed snapshots are logged and skipped
+func snapshotHotSearch(interval time.Duration, top int, dir string) {
+	for {
+		if err := writeHotSearchSnapshot(top, dir); err != nil {
+			log.Printf("snapshot hot search failed: %v", err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func writeHotSearchSnapshot(top int, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, time.Now().Format("2006-01-02")+".jsonl")
+	output, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+	encoder := json.NewEncoder(output)
+	var writeErr error
+	consumer := func(article *model.Article) {
+		if writeErr == nil {
+			writeErr = encoder.Encode(article)
+		}
+	}
+	if err := runService("weibo hot search", consumer, optionValue("TopN", top)); err != nil {
+		return err
+	}
+	return writeErr
+}
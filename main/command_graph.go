@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/export"
+	"github.com/ArchiveLife/weibo/provision"
+	"github.com/urfave/cli"
+)
+
+var commandGraph = cli.Command{
+	Name:   "graph",
+	Usage:  "export social graph of weibo user to GraphML or GEXF",
+	Action: graph,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "uid",
+			Usage: "uid of weibo user",
+		},
+		cli.StringFlag{
+			Name:   "cookie-sub",
+			EnvVar: "WEIBO_COOKIE_SUB",
+			Usage:  "the 'SUB' part of cookie, weibo only shows part of the lists without login",
+		},
+		cli.IntFlag{
+			Name:  "max-pages",
+			Usage: "max pages of followings and followers to read, unlimited by default",
+		},
+		cli.BoolFlag{
+			Name:  "with-posts",
+			Usage: "also read the posts of user, for the repost and mention edges",
+		},
+		cli.BoolFlag{
+			Name:  "public-only",
+			Usage: "exclude the non-public posts, e.g. friends-only, fans-only, private or paid ones",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: "graphml",
+			Usage: "'graphml' or 'gexf'",
+		},
+		cli.StringFlag{
+			Name:  "output, o",
+			Usage: "output file, 'weibo.graphml' or 'weibo.gexf' by default",
+		},
+	},
+}
+
+func graph(c *cli.Context) error {
+	uid := c.String("uid")
+	if len(uid) == 0 {
+		return cli.NewExitError("must provide uid", 1)
+	}
+	format := c.String("format")
+	if format != "graphml" && format != "gexf" {
+		return cli.NewExitError(fmt.Sprintf("unknown format '%s'", format), 1)
+	}
+	g := export.NewGraph()
+	g.PublicOnly = c.Bool("public-only")
+	consumer := func(article *model.Article) { g.AddArticle(article) }
+
+	if err := runService("weibo social graph", consumer,
+		optionValue("Uid", uid),
+		optionValue("CookieSub", c.String("cookie-sub")),
+		optionValue("MaxPages", c.Int("max-pages")),
+	); err != nil {
+		return err
+	}
+	if c.Bool("with-posts") {
+		if err := runService("weibo user", consumer, optionValue("Uid", uid)); err != nil {
+			return err
+		}
+	}
+
+	path := c.String("output")
+	if len(path) == 0 {
+		path = "weibo." + format
+	}
+	output, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+	if format == "gexf" {
+		return g.WriteGEXF(output)
+	}
+	return g.WriteGraphML(output)
+}
+
+// runService provided by weibo provision by name
+func runService(name string, consumer adapter.ArticleConsumer, values ...*adapter.OptionValue) error {
+	for _, service := range (provision.WeiboServiceProvision{}).ProvideServices() {
+		if service.GetName() == name {
+			return service.Run(consumer, values...)
+		}
+	}
+	return fmt.Errorf("service '%s' not found", name)
+}
+
+func optionValue(name string, value interface{}) *adapter.OptionValue {
+	return &adapter.OptionValue{Option: adapter.Option{Name: name}, Value: value}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/urfave/cli"
+)
+
+var commandSchema = cli.Command{
+	Name:   "schema",
+	Usage:  "check weibo responses against known schema, report unknown or changed fields",
+	Action: schema,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "uid",
+			Value: "2656274875",
+			Usage: "uid of weibo user to check the user pages",
+		},
+		cli.IntFlag{
+			Name:  "pages",
+			Value: 1,
+			Usage: "count of user pages to check",
+		},
+		cli.StringFlag{
+			Name:   "cookie-sub",
+			EnvVar: "WEIBO_COOKIE_SUB",
+			Usage:  "the 'SUB' part of cookie, to check the timeline",
+		},
+	},
+}
+
+func schema(c *cli.Context) error {
+	weiboAPI := api.NewWeiboAPI()
+	weiboAPI.EnableStrictDecode()
+
+	uid := c.String("uid")
+	for page := 1; page <= c.Int("pages"); page++ {
+		if _, err := weiboAPI.GetUserPagesIndex(uid, page); err != nil {
+			return err
+		}
+	}
+	if sub := c.String("cookie-sub"); len(sub) > 0 {
+		if _, err := weiboAPI.GetTimeLine(sub, ""); err != nil {
+			return err
+		}
+	}
+
+	drifted := false
+	for _, drift := range weiboAPI.GetSchemaDrift() {
+		fmt.Printf("%s (%d responses)\n", drift.Endpoint, drift.Responses)
+		printDrift("unknown", drift.Unknown)
+		printDrift("changed", drift.Changed)
+		drifted = drifted || drift.HasDrift()
+	}
+	if drifted {
+		return cli.NewExitError("weibo response schema drifted", 1)
+	}
+	return nil
+}
+
+func printDrift(kind string, fields map[string]int) {
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Printf("  %s\t%s\t%d\n", kind, path, fields[path])
+	}
+}
@@ -28,6 +28,8 @@ func main() {
 
 	commonCommands := []cli.Command{
 		commandEntry,
+		commandSchema,
+		commandGraph,
 	}
 
 	daemonCommands, err := createDaemonCommands(AppName, AppUsage)
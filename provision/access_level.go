@@ -0,0 +1,98 @@
+package provision
+
+import (
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// values of EXT_ACCESS_LEVEL, from the widest audience to the narrowest
+const (
+	ACCESS_PUBLIC = "public"
+	// ACCESS_FANS visible to fans (粉丝可见)
+	ACCESS_FANS = "fans"
+	// ACCESS_PAID paid or member-only content, e.g. 付费微博, 铁粉专享
+	ACCESS_PAID = "paid"
+	// ACCESS_FRIENDS visible to mutual followings (好友圈, 密友)
+	ACCESS_FRIENDS = "friends"
+	// ACCESS_GROUP visible to a friend group (分组可见), see EXT_VISIBLE_LIST_ID
+	ACCESS_GROUP = "group"
+	// ACCESS_PRIVATE visible to the author only (仅自己可见)
+	ACCESS_PRIVATE = "private"
+	// ACCESS_RESTRICTED unknown kind of restriction
+	ACCESS_RESTRICTED = "restricted"
+)
+
+// raw values of Visible.Type
+const (
+	VISIBLE_TYPE_PUBLIC  = 0
+	VISIBLE_TYPE_PRIVATE = 1
+	VISIBLE_TYPE_GROUP   = 3
+	VISIBLE_TYPE_CLOSE   = 4
+	VISIBLE_TYPE_FRIENDS = 6
+	VISIBLE_TYPE_FANS    = 10
+)
+
+// accessLevel of post, the visible type wins over the paid mark because
+// it is narrower
+func accessLevel(visible *api.Visible, paid, contentAuth bool) string {
+	visibleType := int64(VISIBLE_TYPE_PUBLIC)
+	if visible != nil {
+		visibleType = visible.Type
+	}
+	switch visibleType {
+	case VISIBLE_TYPE_PUBLIC:
+	case VISIBLE_TYPE_PRIVATE:
+		return ACCESS_PRIVATE
+	case VISIBLE_TYPE_GROUP:
+		return ACCESS_GROUP
+	case VISIBLE_TYPE_CLOSE, VISIBLE_TYPE_FRIENDS:
+		return ACCESS_FRIENDS
+	case VISIBLE_TYPE_FANS:
+		return ACCESS_FANS
+	default:
+		return ACCESS_RESTRICTED
+	}
+	if paid {
+		return ACCESS_PAID
+	}
+	if contentAuth {
+		return ACCESS_RESTRICTED
+	}
+	return ACCESS_PUBLIC
+}
+
+// mblogAccessLevel of post
+func mblogAccessLevel(mblog *api.Mblog) string {
+	return accessLevel(mblog.Visible, mblog.IsPaid != nil && *mblog.IsPaid, mblog.ContentAuth != nil && *mblog.ContentAuth != 0)
+}
+
+// setAccessLevel of article, e.g. a comment is visible to the audience of the commented post
+func setAccessLevel(article *model.Article, level string) {
+	if article.ExtAttributes == nil {
+		article.ExtAttributes = map[string]interface{}{}
+	}
+	article.ExtAttributes[EXT_ACCESS_LEVEL] = level
+}
+
+// AccessLevel of archived article, articles without the level are restricted
+// because their audience is unknown, so they never leak into shareable archives
+func AccessLevel(article *model.Article) string {
+	if level, ok := article.ExtAttributes[EXT_ACCESS_LEVEL].(string); ok && len(level) > 0 {
+		return level
+	}
+	return ACCESS_RESTRICTED
+}
+
+// IsPublic article, could be shared with anyone
+func IsPublic(article *model.Article) bool {
+	return AccessLevel(article) == ACCESS_PUBLIC
+}
+
+// withAccessLevel of every article, the restricted level is set if the article has none
+func withAccessLevel(consumer adapter.ArticleConsumer) adapter.ArticleConsumer {
+	return func(article *model.Article) {
+		setAccessLevel(article, AccessLevel(article))
+		consumer(article)
+	}
+}
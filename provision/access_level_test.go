@@ -0,0 +1,91 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLevel_Mapping(t *testing.T) {
+	cases := []struct {
+		name        string
+		visible     *api.Visible
+		paid        bool
+		contentAuth bool
+		level       string
+	}{
+		{"no visible", nil, false, false, ACCESS_PUBLIC},
+		{"public", &api.Visible{Type: VISIBLE_TYPE_PUBLIC}, false, false, ACCESS_PUBLIC},
+		{"private", &api.Visible{Type: VISIBLE_TYPE_PRIVATE}, false, false, ACCESS_PRIVATE},
+		{"group", &api.Visible{Type: VISIBLE_TYPE_GROUP, ListID: 42}, false, false, ACCESS_GROUP},
+		{"close friends", &api.Visible{Type: VISIBLE_TYPE_CLOSE}, false, false, ACCESS_FRIENDS},
+		{"friends", &api.Visible{Type: VISIBLE_TYPE_FRIENDS}, false, false, ACCESS_FRIENDS},
+		{"fans", &api.Visible{Type: VISIBLE_TYPE_FANS}, false, false, ACCESS_FANS},
+		{"unknown visible type", &api.Visible{Type: 99}, false, false, ACCESS_RESTRICTED},
+		{"paid", &api.Visible{Type: VISIBLE_TYPE_PUBLIC}, true, false, ACCESS_PAID},
+		{"content auth", &api.Visible{Type: VISIBLE_TYPE_PUBLIC}, false, true, ACCESS_RESTRICTED},
+		// paid wins over content auth
+		{"paid with content auth", nil, true, true, ACCESS_PAID},
+		// the visible type is narrower than paid
+		{"paid for fans", &api.Visible{Type: VISIBLE_TYPE_FANS}, true, true, ACCESS_FANS},
+		{"paid private", &api.Visible{Type: VISIBLE_TYPE_PRIVATE}, true, false, ACCESS_PRIVATE},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.level, accessLevel(c.visible, c.paid, c.contentAuth))
+		})
+	}
+}
+
+func TestAccessLevel_Article(t *testing.T) {
+	assert := assert.New(t)
+
+	// the audience of article without level is unknown
+	assert.Equal(ACCESS_RESTRICTED, AccessLevel(&model.Article{}))
+	assert.False(IsPublic(&model.Article{}))
+
+	var consumed []*model.Article
+	consumer := withAccessLevel(func(article *model.Article) { consumed = append(consumed, article) })
+	consumer(&model.Article{})
+	consumer(&model.Article{ExtAttributes: map[string]interface{}{EXT_ACCESS_LEVEL: ACCESS_PUBLIC}})
+	assert.Equal(ACCESS_RESTRICTED, consumed[0].ExtAttributes[EXT_ACCESS_LEVEL])
+	assert.Equal(ACCESS_PUBLIC, consumed[1].ExtAttributes[EXT_ACCESS_LEVEL])
+}
+
+func TestAccessLevel_Converted(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newWeiboConvertor(api.NewWeiboAPI(), newIdSet())
+	articles := c.convertMblog(parseMblog(t, `{
+		"id": "2",
+		"text": "repost for friends",
+		"user": {"id": 2},
+		"visible": {"type": 6},
+		"retweeted_status": {"id": "1", "text": "deleted", "deleted": "1"}
+	}`))
+	assert.Len(articles, 2)
+	assert.Equal(ACCESS_FRIENDS, AccessLevel(articles[0]))
+	// the deleted original is restricted
+	assert.Equal(ACCESS_RESTRICTED, AccessLevel(articles[1]))
+
+	r := &MyActivityWeiboReader{activityReader: activityReader{convertor: c}}
+	comments := r.convertComment(&api.Comment{
+		ID:     "3",
+		Text:   "comment on private post",
+		Status: parseMblog(t, `{"id": "4", "user": {"id": 1}, "visible": {"type": 1}}`),
+	})
+	assert.Len(comments, 2)
+	assert.Equal(KEY_WEIBO_COMMENT_TYPE, comments[1].Type)
+	// the comment is visible to the audience of commented post
+	assert.Equal(ACCESS_PRIVATE, AccessLevel(comments[1]))
+	// the audience of comment without the commented post is unknown
+	orphan := r.convertComment(&api.Comment{ID: "5", Text: "orphan"})
+	assert.Equal(ACCESS_RESTRICTED, AccessLevel(orphan[0]))
+
+	user := c.convertUserArticle(&api.User{ID: 9, ScreenName: "public user"})
+	assert.Equal(ACCESS_PUBLIC, AccessLevel(user))
+	// the author keeps only the user keys
+	assert.NotContains(user.Author.ExtAttributes, EXT_ACCESS_LEVEL)
+}
@@ -0,0 +1,245 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// activity of the account, e.g. favorite, comment or like
+type activity struct {
+	// id of activity, e.g. the comment id or the post id, to stop at the archived
+	// activities in incremental mode. empty if unknown
+	id string
+	// date of activity, e.g. time of favorite, nil if unknown and not limited by date range
+	date *time.Time
+	// convert the activity to articles, called only for the unarchived activities
+	convert func() []*model.Article
+}
+
+// count of recent activity ids kept in checkpoint
+const RECENT_ACTIVITY_IDS = 20
+
+// activityPageReader of activities, newest first, page starts from 1, empty if no more
+type activityPageReader func(page int) ([]activity, error)
+
+func createActivityOptions(order int) []*adapter.Option {
+	cookieSubLabel := "Cookie SUB"
+	cookieSubDesc := "the 'SUB' part of cookie of the account"
+	maxPagesLabel := "Max Pages"
+	maxPagesDesc := "max pages to read, unlimited by default"
+	options := []*adapter.Option{
+		{
+			Order:       order,
+			Name:        "CookieSub",
+			Label:       &cookieSubLabel,
+			Description: &cookieSubDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       order + 1,
+			Name:        "MaxPages",
+			Label:       &maxPagesLabel,
+			Description: &maxPagesDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+	}
+	options = append(options, createRawOptions(order+2)...)
+	options = append(options, createCheckpointOptions(order+4)...)
+	return append(options, createDateRangeOptions(order+6)...)
+}
+
+// activityReader read the activities of the logged-in account, newest first. in incremental mode
+// it stops once reaching the newest activity of previous complete run
+type activityReader struct {
+	RawOptions
+	CheckpointOptions
+	DateRangeOptions
+	CookieSub   string
+	MaxPages    int
+	uid         string
+	api         *api.WeiboAPI
+	convertor   *weiboConvertor
+	retry       retryPolicy
+	err         error
+	name        string
+	feedKey     string
+	readPage    activityPageReader
+	currentPage int
+	tmp         []*model.Article
+	stopped     bool
+	checkpoints CheckpointStore
+	// checkpoint of previous runs, empty if not incremental
+	archived Checkpoint
+	// newest activity id and time of this run
+	newestID string
+	newestAt *time.Time
+	// ids of activities read in this run, newest first, at most RECENT_ACTIVITY_IDS
+	recentIDs []string
+	period    dateRange
+	// ids are not ordered by the time of activity, e.g. the post ids of favorites and likes
+	unorderedIds bool
+}
+
+// initActivities named for errors, the checkpoint is stored under feedKey and uid of account
+func (r *activityReader) initActivities(name string, feedKey string, readPage activityPageReader) error {
+	if len(r.CookieSub) == 0 {
+		return errors.New("must provide the 'SUB' part of cookie")
+	}
+	r.api = api.NewWeiboAPI()
+	r.convertor = newWeiboConvertor(r.api, newIdSet())
+	r.retry = defaultRetryPolicy
+	r.err = nil
+	r.name = name
+	r.feedKey = feedKey
+	r.readPage = readPage
+	r.currentPage = 0
+	r.tmp = nil
+	r.stopped = false
+	r.archived = Checkpoint{}
+	r.newestID = ""
+	r.newestAt = nil
+	r.recentIDs = nil
+	var err error
+	if r.period, err = r.DateRangeOptions.dateRange(); err != nil {
+		return err
+	}
+	if r.checkpoints, err = r.CheckpointOptions.store(); err != nil {
+		return err
+	}
+	if err := r.retry.do(func() (err error) {
+		r.uid, err = r.api.GetLoginUid(r.CookieSub)
+		return err
+	}); err != nil {
+		return err
+	}
+	if r.checkpoints != nil && r.Incremental {
+		checkpoint, err := r.checkpoints.Load(r.feedKey + r.uid)
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil {
+			r.archived = *checkpoint
+		}
+	}
+	return r.RawOptions.apply(r.convertor)
+}
+
+func (r *activityReader) Next() (*model.Article, bool) {
+	for len(r.tmp) == 0 {
+		if r.err != nil {
+			return nil, false
+		}
+		if r.stopped {
+			r.err = r.saveCheckpoint()
+			return nil, false
+		}
+		// the older activities are not read, so the checkpoint is kept
+		if r.MaxPages > 0 && r.currentPage >= r.MaxPages {
+			return nil, false
+		}
+		r.currentPage++
+		var activities []activity
+		if err := r.retry.do(func() (err error) {
+			activities, err = r.readPage(r.currentPage)
+			return err
+		}); err != nil {
+			r.err = fmt.Errorf("read page %d of %s failed: %w", r.currentPage, r.name, err)
+			return nil, false
+		}
+		if len(activities) == 0 {
+			r.stopped = true
+			continue
+		}
+		r.tmp = r.convertActivities(activities)
+	}
+	rt := r.tmp[0]
+	r.tmp = r.tmp[1:]
+	return rt, true
+}
+
+// Err stopped reading, nil if all activities have been read
+func (r *activityReader) Err() error {
+	return r.err
+}
+
+// saveCheckpoint once all activities of this run have been read
+func (r *activityReader) saveCheckpoint() error {
+	if r.checkpoints == nil || len(r.newestID) == 0 {
+		return nil
+	}
+	// the recent ids of previous runs are kept, in case the few activities of this run are removed
+	recentIDs := append(append([]string{}, r.recentIDs...), r.archived.RecentIDs...)
+	if len(recentIDs) > RECENT_ACTIVITY_IDS {
+		recentIDs = recentIDs[:RECENT_ACTIVITY_IDS]
+	}
+	newestAt := r.newestAt
+	if newestAt == nil || (r.archived.NewestAt != nil && r.archived.NewestAt.After(*newestAt)) {
+		newestAt = r.archived.NewestAt
+	}
+	return r.checkpoints.Save(r.feedKey+r.uid, &Checkpoint{NewestID: r.newestID, RecentIDs: recentIDs, NewestAt: newestAt})
+}
+
+func (r *activityReader) convertActivities(activities []activity) (rt []*model.Article) {
+	for _, a := range activities {
+		if r.reachArchived(a) {
+			r.stopped = true
+			break
+		}
+		if r.period.tooNew(a.date) {
+			continue
+		}
+		if r.period.tooOld(a.date) {
+			// the following activities are older than the range
+			r.stopped = true
+			break
+		}
+		if len(r.newestID) == 0 || (!r.unorderedIds && isNewer(a.id, r.newestID)) {
+			r.newestID = a.id
+		}
+		if a.date != nil && (r.newestAt == nil || a.date.After(*r.newestAt)) {
+			r.newestAt = a.date
+		}
+		if len(a.id) > 0 && len(r.recentIDs) < RECENT_ACTIVITY_IDS {
+			r.recentIDs = append(r.recentIDs, a.id)
+		}
+		articles := a.convert()
+		// the activity is left to the next run, without saving the checkpoint
+		if err := r.convertor.Err(); err != nil {
+			r.err = fmt.Errorf("convert activity '%s' of %s failed: %w", a.id, r.name, err)
+			break
+		}
+		rt = append(rt, articles...)
+	}
+	return rt
+}
+
+// reachArchived activity, the archived ones may have been removed, e.g. unfavorited, so the run
+// stops at any recent archived activity, any activity older than the newest archived one by time,
+// or by id if ids are ordered
+func (r *activityReader) reachArchived(a activity) bool {
+	if len(a.id) > 0 {
+		for _, id := range r.archived.RecentIDs {
+			if a.id == id {
+				return true
+			}
+		}
+		if len(r.archived.NewestID) > 0 {
+			if r.unorderedIds && a.id == r.archived.NewestID {
+				return true
+			}
+			if !r.unorderedIds && !isNewer(a.id, r.archived.NewestID) {
+				return true
+			}
+		}
+	}
+	// the activities of the same second are not ordered by time
+	return a.date != nil && r.archived.NewestAt != nil && a.date.Before(*r.archived.NewestAt)
+}
@@ -0,0 +1,144 @@
+package provision
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+var activityBase = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// activityAt second after base, without date if second is negative
+func activityAt(id string, second int) activity {
+	a := activity{id: id}
+	if second >= 0 {
+		date := activityBase.Add(time.Duration(second) * time.Second)
+		a.date = &date
+	}
+	return a
+}
+
+func readActivities(r *activityReader, activities ...activity) (converted []string) {
+	if r.convertor == nil {
+		r.convertor = newWeiboConvertor(api.NewWeiboAPI(), newIdSet())
+	}
+	for i := range activities {
+		id := activities[i].id
+		activities[i].convert = func() []*model.Article {
+			converted = append(converted, id)
+			return nil
+		}
+	}
+	r.convertActivities(activities)
+	return converted
+}
+
+func TestActivityReader_StopAtArchived(t *testing.T) {
+	assert := assert.New(t)
+
+	// the archived activity '3' has been removed, e.g. unfavorited
+	r := &activityReader{archived: Checkpoint{NewestID: "3"}}
+	assert.Equal([]string{"5", "4"}, readActivities(r, activityAt("5", -1), activityAt("4", -1), activityAt("2", -1), activityAt("1", -1)))
+	assert.True(r.stopped)
+	assert.Equal("5", r.newestID)
+	assert.Equal([]string{"5", "4"}, r.recentIDs)
+
+	// the activity without id never stops the run
+	r = &activityReader{archived: Checkpoint{NewestID: "3"}}
+	assert.Equal([]string{""}, readActivities(r, activityAt("", -1), activityAt("2", -1)))
+	assert.True(r.stopped)
+}
+
+func TestActivityReader_UnorderedIds(t *testing.T) {
+	assert := assert.New(t)
+
+	// the ids of likes are post ids, only the archived ones stop the run
+	r := &activityReader{archived: Checkpoint{NewestID: "3"}, unorderedIds: true}
+	assert.Equal([]string{"7", "1"}, readActivities(r, activityAt("7", -1), activityAt("1", -1), activityAt("3", -1), activityAt("9", -1)))
+	assert.True(r.stopped)
+	assert.Equal("7", r.newestID)
+
+	// the newest archived one '3' has been removed, e.g. unliked, the run stops at the recent one
+	r = &activityReader{archived: Checkpoint{NewestID: "3", RecentIDs: []string{"3", "8", "2"}}, unorderedIds: true}
+	assert.Equal([]string{"7", "1"}, readActivities(r, activityAt("7", -1), activityAt("1", -1), activityAt("8", -1), activityAt("2", -1)))
+	assert.True(r.stopped)
+}
+
+func TestActivityReader_SameSecond(t *testing.T) {
+	assert := assert.New(t)
+
+	// favorites of the same second, the archived one may be read after the new one
+	archivedAt := activityBase.Add(10 * time.Second)
+	r := &activityReader{archived: Checkpoint{NewestID: "3", RecentIDs: []string{"3"}, NewestAt: &archivedAt}, unorderedIds: true}
+	assert.Equal([]string{"9", "5"}, readActivities(r, activityAt("9", 11), activityAt("5", 10), activityAt("3", 10), activityAt("1", 9)))
+	assert.True(r.stopped)
+	assert.Equal("9", r.newestID)
+
+	// the archived one has been removed, the run stops at the older activity
+	r = &activityReader{archived: Checkpoint{NewestID: "3", RecentIDs: []string{"3"}, NewestAt: &archivedAt}, unorderedIds: true}
+	assert.Equal([]string{"9", "5"}, readActivities(r, activityAt("9", 11), activityAt("5", 10), activityAt("1", 9), activityAt("2", 8)))
+	assert.True(r.stopped)
+}
+
+func TestActivityReader_SaveCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	archivedAt := activityBase.Add(10 * time.Second)
+	r := &activityReader{
+		checkpoints:  NewFileCheckpointStore(path),
+		feedKey:      "weibo favorites:",
+		uid:          "1",
+		archived:     Checkpoint{NewestID: "3", RecentIDs: []string{"3", "8"}, NewestAt: &archivedAt},
+		unorderedIds: true,
+	}
+	readActivities(r, activityAt("7", 12), activityAt("1", 11), activityAt("3", 10))
+	assert.Nil(r.saveCheckpoint())
+	checkpoint, err := NewFileCheckpointStore(path).Load("weibo favorites:1")
+	assert.Nil(err)
+	assert.Equal("7", checkpoint.NewestID)
+	// the recent ids of previous runs are kept after the new ones
+	assert.Equal([]string{"7", "1", "3", "8"}, checkpoint.RecentIDs)
+	assert.True(activityBase.Add(12 * time.Second).Equal(*checkpoint.NewestAt))
+
+	// at most RECENT_ACTIVITY_IDS are kept
+	r.archived = *checkpoint
+	r.newestID, r.recentIDs = "", nil
+	var activities []activity
+	for id := 100 + RECENT_ACTIVITY_IDS; id > 100; id-- {
+		activities = append(activities, activityAt(fmt.Sprint(id), -1))
+	}
+	readActivities(r, activities...)
+	assert.Nil(r.saveCheckpoint())
+	checkpoint, _ = NewFileCheckpointStore(path).Load("weibo favorites:1")
+	assert.Len(checkpoint.RecentIDs, RECENT_ACTIVITY_IDS)
+	assert.Equal(fmt.Sprint(100+RECENT_ACTIVITY_IDS), checkpoint.RecentIDs[0])
+	// the time of previous runs is kept if activities of this run are not dated
+	assert.True(activityBase.Add(12 * time.Second).Equal(*checkpoint.NewestAt))
+}
+
+func TestFavoritesWeiboReader_FavoriteActivity(t *testing.T) {
+	assert := assert.New(t)
+
+	r := &FavoritesWeiboReader{}
+	r.convertor = newWeiboConvertor(api.NewWeiboAPI(), newIdSet())
+	favorited := parseMblog(t, `{"id": "4000000000000001", "favorited_time": "Mon Oct 19 10:00:00 +0800 2026"}`)
+	unknown := parseMblog(t, `{"id": "4000000000000002"}`)
+
+	// the post id identifies the favorite, even if the time of favorite is missing
+	a := r.favoriteActivity(favorited)
+	assert.Equal("4000000000000001", a.id)
+	assert.Equal(time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC).Unix(), a.date.Unix())
+	a = r.favoriteActivity(unknown)
+	assert.Equal("4000000000000002", a.id)
+	assert.Nil(a.date)
+
+	articles := r.favoriteActivity(favorited).convert()
+	assert.Len(articles, 1)
+	assert.Equal("2026-10-19T10:00:00+08:00", articles[0].ExtAttributes[EXT_FAVORITED_AT])
+}
@@ -0,0 +1,47 @@
+package provision
+
+import (
+	"log"
+
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// card types of recommendation, ad or decoration, which contain no post
+var skippedCardTypes = map[int64]bool{
+	api.CARD_TYPE_USER:     true,
+	api.CARD_TYPE_USER_BOX: true,
+	api.CARD_TYPE_BANNER:   true,
+	api.CARD_TYPE_SEARCH:   true,
+	api.CARD_TYPE_TITLE:    true,
+	api.CARD_TYPE_TIPS:     true,
+	api.CARD_TYPE_PHOTOS:   true,
+}
+
+// extractMblogs from all cards of a list page, including the posts nested in card groups.
+// recommendation and ad cards are skipped, unknown card types are reported
+func extractMblogs(cards []api.Card) (rt []*api.Mblog) {
+	for _, card := range cards {
+		switch {
+		case card.CardType == api.CARD_TYPE_MBLOG:
+			if card.Mblog != nil {
+				rt = append(rt, card.Mblog)
+			}
+		case card.CardType == api.CARD_TYPE_GROUP:
+			for _, group := range card.CardGroup {
+				switch {
+				case group.CardType == api.CARD_TYPE_MBLOG:
+					if group.Mblog != nil {
+						rt = append(rt, group.Mblog)
+					}
+				case skippedCardTypes[group.CardType]:
+				default:
+					log.Printf("skip unknown card type %d in card group '%s'", group.CardType, stringOf(card.Itemid))
+				}
+			}
+		case skippedCardTypes[card.CardType]:
+		default:
+			log.Printf("skip unknown card type %d, item '%s'", card.CardType, stringOf(card.Itemid))
+		}
+	}
+	return rt
+}
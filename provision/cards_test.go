@@ -0,0 +1,38 @@
+package provision
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractMblogs(t *testing.T) {
+	assert := assert.New(t)
+
+	page := &api.WeiboUserListPageIndex{}
+	data := `{"ok":1,"data":{"cards":[
+		{"card_type":9,"mblog":{"id":"1"}},
+		{"card_type":22,"itemid":"banner"},
+		{"card_type":11,"itemid":"group","card_group":[
+			{"card_type":42},
+			{"card_type":9,"mblog":{"id":"2"}},
+			{"card_type":10},
+			{"card_type":999},
+			{"card_type":9,"mblog":{"id":"3"}}
+		]},
+		{"card_type":9},
+		{"card_type":998,"itemid":"unknown"},
+		{"card_type":9,"mblog":{"id":"4"}}
+	]}}`
+	if err := json.Unmarshal([]byte(data), page); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, mblog := range extractMblogs(page.Data.Cards) {
+		ids = append(ids, *mblog.ID)
+	}
+	assert.Equal([]string{"1", "2", "3", "4"}, ids)
+	assert.Len(extractMblogs(nil), 0)
+}
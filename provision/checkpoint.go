@@ -0,0 +1,166 @@
+package provision
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ArchiveLife/core/adapter"
+)
+
+// Checkpoint of an archived weibo feed
+type Checkpoint struct {
+	// NewestID of archived post, incremental run stops once reaching it
+	NewestID string `json:"newest_id"`
+	// Cursor (page) to resume the history crawl from, 0 if the history has been fully archived
+	Cursor int `json:"cursor"`
+	// RecentIDs of archived activities, newest first, incremental run stops at any of them,
+	// in case the newest one has been removed, e.g. unfavorited
+	RecentIDs []string `json:"recent_ids,omitempty"`
+	// NewestAt time of archived activity, incremental run stops at the older activities
+	NewestAt *time.Time `json:"newest_at,omitempty"`
+	// UpdatedAt time of checkpoint
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore persist checkpoints, keyed by service and feed (e.g. uid)
+type CheckpointStore interface {
+	// Load checkpoint, nil if not exist
+	Load(key string) (*Checkpoint, error)
+	Save(key string, checkpoint *Checkpoint) error
+}
+
+// file checkpoint stores by path, so that readers in goroutines share the same lock of file
+var fileCheckpointStores = struct {
+	sync.Mutex
+	stores map[string]*fileCheckpointStore
+}{stores: map[string]*fileCheckpointStore{}}
+
+// NewFileCheckpointStore keep all checkpoints in a single json file
+func NewFileCheckpointStore(path string) CheckpointStore {
+	fileCheckpointStores.Lock()
+	defer fileCheckpointStores.Unlock()
+	if store, found := fileCheckpointStores.stores[path]; found {
+		return store
+	}
+	store := &fileCheckpointStore{path: path}
+	fileCheckpointStores.stores[path] = store
+	return store
+}
+
+type fileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileCheckpointStore) read() (map[string]*Checkpoint, error) {
+	rt := map[string]*Checkpoint{}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return rt, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &rt); err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+func (s *fileCheckpointStore) Load(key string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoints, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return checkpoints[key], nil
+}
+
+func (s *fileCheckpointStore) Save(key string, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoint.UpdatedAt = time.Now()
+	checkpoints[key] = checkpoint
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.path); len(dir) > 0 {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	// write to temp file then rename, avoid broken file on crash
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// CheckpointOptions of weibo services, embedded in readers to archive incrementally
+type CheckpointOptions struct {
+	Incremental    bool
+	CheckpointFile string
+}
+
+func createCheckpointOptions(order int) []*adapter.Option {
+	incrementalLabel := "Incremental"
+	incrementalDesc := "stop once reaching the archived posts recorded in checkpoint, and resume the unfinished history"
+	checkpointFileLabel := "Checkpoint File"
+	checkpointFileDesc := "json file to persist the checkpoints of archived posts"
+	return []*adapter.Option{
+		{
+			Order:       order,
+			Name:        "Incremental",
+			Label:       &incrementalLabel,
+			Description: &incrementalDesc,
+			Optional:    true,
+			ValueType:   reflect.Bool,
+		},
+		{
+			Order:       order + 1,
+			Name:        "CheckpointFile",
+			Label:       &checkpointFileLabel,
+			Description: &checkpointFileDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+	}
+}
+
+// store of checkpoint, nil if checkpoint file is not provided,
+// incremental mode without checkpoint file would silently run a full crawl
+func (o *CheckpointOptions) store() (CheckpointStore, error) {
+	if len(o.CheckpointFile) == 0 {
+		if o.Incremental {
+			return nil, errors.New("must provide checkpoint file in incremental mode")
+		}
+		return nil, nil
+	}
+	return NewFileCheckpointStore(o.CheckpointFile), nil
+}
+
+// isNewer post id, weibo post ids are increasing by time
+func isNewer(id, than string) bool {
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return false
+	}
+	j, err := strconv.ParseInt(than, 10, 64)
+	if err != nil {
+		return true
+	}
+	return i > j
+}
@@ -0,0 +1,225 @@
+package provision
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCheckpointStore(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "nested", "checkpoints.json")
+	store := NewFileCheckpointStore(path)
+	assert.Same(store, NewFileCheckpointStore(path))
+
+	checkpoint, err := store.Load("weibo user:1")
+	assert.Nil(err)
+	assert.Nil(checkpoint)
+
+	assert.Nil(store.Save("weibo user:1", &Checkpoint{NewestID: "42", Cursor: 3}))
+	assert.Nil(store.Save("weibo user:2", &Checkpoint{NewestID: "7"}))
+
+	// read from file by another store
+	checkpoint, err = (&fileCheckpointStore{path: path}).Load("weibo user:1")
+	assert.Nil(err)
+	assert.Equal("42", checkpoint.NewestID)
+	assert.Equal(3, checkpoint.Cursor)
+	assert.False(checkpoint.UpdatedAt.IsZero())
+	checkpoint, err = store.Load("weibo user:2")
+	assert.Nil(err)
+	assert.Equal("7", checkpoint.NewestID)
+}
+
+func TestCheckpointOptions_Store(t *testing.T) {
+	assert := assert.New(t)
+
+	store, err := (&CheckpointOptions{}).store()
+	assert.Nil(err)
+	assert.Nil(store)
+	_, err = (&CheckpointOptions{Incremental: true}).store()
+	assert.NotNil(err)
+}
+
+func TestIsNewer(t *testing.T) {
+	cases := []struct {
+		id, than string
+		newer    bool
+	}{
+		{"4600000000000002", "4600000000000001", true},
+		{"4600000000000001", "4600000000000001", false},
+		{"4600000000000001", "4600000000000002", false},
+		// any post is newer than no checkpoint
+		{"4600000000000001", "", true},
+		{"", "4600000000000001", false},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.newer, isNewer(c.id, c.than), "%s newer than %s", c.id, c.than)
+	}
+}
+
+// mblogOf id, which is published on the id-th day of 2026
+func mblogOf(id int) *api.Mblog {
+	mblogId := fmt.Sprint(id)
+	createdAt := time.Date(2026, 1, id, 12, 0, 0, 0, time.UTC).Format(time.RubyDate)
+	return &api.Mblog{ID: &mblogId, CreatedAt: &createdAt}
+}
+
+// stubPages of feed, the pages after the last one are empty
+func stubPages(read *[]int, pages ...[]int) pageReader {
+	return func(page int) ([]*api.Mblog, bool, error) {
+		*read = append(*read, page)
+		if page > len(pages) {
+			return nil, false, nil
+		}
+		var mblogs []*api.Mblog
+		for _, id := range pages[page-1] {
+			mblogs = append(mblogs, mblogOf(id))
+		}
+		return mblogs, true, nil
+	}
+}
+
+func postIDs(ids ...int) (rt []model.ID) {
+	for _, id := range ids {
+		rt = append(rt, model.CreateID(KEY_WEIBO_ARTICLE_TYPE, fmt.Sprint(id)))
+	}
+	return rt
+}
+
+func readFeed(t *testing.T, r *feedReader, readPage pageReader) (rt []model.ID) {
+	if err := r.initFeed("stub", "stub:1", readPage); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		article, ok := r.Next()
+		if !ok {
+			break
+		}
+		rt = append(rt, article.ID)
+	}
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return rt
+}
+
+func TestFeedReader_StopAtArchived(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	options := CheckpointOptions{Incremental: true, CheckpointFile: path}
+	assert.Nil(NewFileCheckpointStore(path).Save("stub:1", &Checkpoint{NewestID: "3"}))
+
+	var read []int
+	r := &feedReader{CheckpointOptions: options}
+	ids := readFeed(t, r, stubPages(&read, []int{5, 4}, []int{3, 2}, []int{1}))
+	assert.Equal(postIDs(5, 4), ids)
+	// the history has been fully archived
+	assert.Equal([]int{1, 2}, read)
+	checkpoint, _ := NewFileCheckpointStore(path).Load("stub:1")
+	assert.Equal("5", checkpoint.NewestID)
+	assert.Equal(0, checkpoint.Cursor)
+}
+
+func TestFeedReader_ResumeFromCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	options := CheckpointOptions{Incremental: true, CheckpointFile: path}
+	// the previous run stopped before page 3
+	assert.Nil(NewFileCheckpointStore(path).Save("stub:1", &Checkpoint{NewestID: "4", Cursor: 3}))
+
+	var read []int
+	r := &feedReader{CheckpointOptions: options}
+	ids := readFeed(t, r, stubPages(&read, []int{6, 5}, []int{4, 3}, []int{2, 1}))
+	assert.Equal(postIDs(6, 5, 2, 1), ids)
+	assert.Equal([]int{1, 2, 3, 4}, read)
+	checkpoint, _ := NewFileCheckpointStore(path).Load("stub:1")
+	assert.Equal("6", checkpoint.NewestID)
+	assert.Equal(0, checkpoint.Cursor)
+}
+
+func TestFeedReader_NoCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	options := CheckpointOptions{Incremental: true, CheckpointFile: path}
+	assert.Nil(NewFileCheckpointStore(path).Save("stub:1", &Checkpoint{NewestID: "4", Cursor: 3}))
+
+	var read []int
+	r := &feedReader{CheckpointOptions: options, noCursor: true}
+	ids := readFeed(t, r, stubPages(&read, []int{6, 5}, []int{4, 3}, []int{2, 1}))
+	// the page at cursor could not be read directly, so the history is not resumed
+	assert.Equal(postIDs(6, 5), ids)
+	assert.Equal([]int{1, 2}, read)
+	checkpoint, _ := NewFileCheckpointStore(path).Load("stub:1")
+	assert.Equal("6", checkpoint.NewestID)
+	assert.Equal(0, checkpoint.Cursor)
+}
+
+func TestFeedReader_StopBeforeRange(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	options := CheckpointOptions{Incremental: true, CheckpointFile: path}
+
+	// the first run stopped at the posts older than range
+	var read []int
+	r := &feedReader{CheckpointOptions: options, DateRangeOptions: DateRangeOptions{Since: "2026-01-04"}}
+	ids := readFeed(t, r, stubPages(&read, []int{6, 5}, []int{4, 3}, []int{2, 1}))
+	assert.Equal(postIDs(6, 5, 4), ids)
+	assert.Equal([]int{1, 2}, read)
+	checkpoint, _ := NewFileCheckpointStore(path).Load("stub:1")
+	assert.Equal("6", checkpoint.NewestID)
+	// the older posts of page 2 are left to the run without date range
+	assert.Equal(2, checkpoint.Cursor)
+
+	// stopped in the head pages, the newest post is checkpointed and the history is kept
+	read = nil
+	r = &feedReader{CheckpointOptions: options, DateRangeOptions: DateRangeOptions{Since: "2026-01-08"}}
+	ids = readFeed(t, r, stubPages(&read, []int{9, 8}, []int{7, 6}, []int{5, 4}, []int{3, 2}, []int{1}))
+	assert.Equal(postIDs(9, 8), ids)
+	assert.Equal([]int{1, 2}, read)
+	checkpoint, _ = NewFileCheckpointStore(path).Load("stub:1")
+	assert.Equal("9", checkpoint.NewestID)
+	assert.Equal(2, checkpoint.Cursor)
+
+	// the history is resumed without date range
+	read = nil
+	r = &feedReader{CheckpointOptions: options}
+	ids = readFeed(t, r, stubPages(&read, []int{9, 8}, []int{7, 6}, []int{5, 4}, []int{3, 2}, []int{1}))
+	assert.Equal(postIDs(7, 6, 5, 4, 3, 2, 1), ids)
+	assert.Equal([]int{1, 2, 3, 4, 5, 6}, read)
+	checkpoint, _ = NewFileCheckpointStore(path).Load("stub:1")
+	assert.Equal("9", checkpoint.NewestID)
+	assert.Equal(0, checkpoint.Cursor)
+}
+
+func TestFeedReader_Unordered(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	assert.Nil(NewFileCheckpointStore(path).Save("stub:1", &Checkpoint{NewestID: "5"}))
+
+	// the archived posts could not be told by id
+	r := &feedReader{CheckpointOptions: CheckpointOptions{Incremental: true, CheckpointFile: path}, unordered: true}
+	assert.NotNil(r.initFeed("stub", "stub:1", stubPages(&[]int{}, []int{3, 7})))
+
+	// all posts of unordered feed are read, including the ones older than checkpoint or range
+	var read []int
+	r = &feedReader{
+		CheckpointOptions: CheckpointOptions{CheckpointFile: path},
+		DateRangeOptions:  DateRangeOptions{Since: "2026-01-03"},
+		unordered:         true,
+	}
+	ids := readFeed(t, r, stubPages(&read, []int{3, 7, 1}, []int{5, 2, 6}))
+	assert.Equal(postIDs(3, 7, 5, 6), ids)
+	assert.Equal([]int{1, 2, 3}, read)
+}
@@ -0,0 +1,164 @@
+package provision
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// values of 'WithMedia' option
+const (
+	MEDIA_ANY     = "any"
+	MEDIA_PICTURE = "picture"
+	MEDIA_VIDEO   = "video"
+)
+
+// ContentFilterOptions of weibo services, embedded in readers to archive part of posts
+type ContentFilterOptions struct {
+	OriginalOnly bool
+	WithMedia    string
+	Include      string
+	Exclude      string
+}
+
+func createContentFilterOptions(order int) []*adapter.Option {
+	originalOnlyLabel := "Original Only"
+	originalOnlyDesc := "only archive original posts, reposts are skipped"
+	withMediaLabel := "With Media"
+	withMediaDesc := "only archive posts with media, 'any', 'picture' or 'video'"
+	includeLabel := "Include"
+	includeDesc := "only archive posts whose text matches this regular expression"
+	excludeLabel := "Exclude"
+	excludeDesc := "skip posts whose text matches this regular expression"
+	return []*adapter.Option{
+		{
+			Order:       order,
+			Name:        "OriginalOnly",
+			Label:       &originalOnlyLabel,
+			Description: &originalOnlyDesc,
+			Optional:    true,
+			ValueType:   reflect.Bool,
+		},
+		{
+			Order:       order + 1,
+			Name:        "WithMedia",
+			Label:       &withMediaLabel,
+			Description: &withMediaDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       order + 2,
+			Name:        "Include",
+			Label:       &includeLabel,
+			Description: &includeDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       order + 3,
+			Name:        "Exclude",
+			Label:       &excludeLabel,
+			Description: &excludeDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+	}
+}
+
+// contentFilter of posts on client side
+type contentFilter struct {
+	originalOnly bool
+	media        string
+	include      *regexp.Regexp
+	exclude      *regexp.Regexp
+}
+
+func (o *ContentFilterOptions) contentFilter() (rt *contentFilter, err error) {
+	rt = &contentFilter{originalOnly: o.OriginalOnly, media: o.WithMedia}
+	switch o.WithMedia {
+	case "", MEDIA_ANY, MEDIA_PICTURE, MEDIA_VIDEO:
+	default:
+		return nil, fmt.Errorf("invalid media '%s', should be '%s', '%s' or '%s'", o.WithMedia, MEDIA_ANY, MEDIA_PICTURE, MEDIA_VIDEO)
+	}
+	if len(o.Include) > 0 {
+		if rt.include, err = regexp.Compile(o.Include); err != nil {
+			return nil, err
+		}
+	}
+	if len(o.Exclude) > 0 {
+		if rt.exclude, err = regexp.Compile(o.Exclude); err != nil {
+			return nil, err
+		}
+	}
+	return rt, nil
+}
+
+// serverFilter of weibo which could reduce the pages to read, empty if none
+func (f *contentFilter) serverFilter() string {
+	switch {
+	case f.originalOnly:
+		return api.FILTER_ORIGINAL
+	case f.media == MEDIA_PICTURE:
+		return api.FILTER_PICTURE
+	case f.media == MEDIA_VIDEO:
+		return api.FILTER_VIDEO
+	}
+	return ""
+}
+
+// signature of filter, posts read with different filters should be checkpointed separately
+func (f *contentFilter) signature() string {
+	var parts []string
+	if f.originalOnly {
+		parts = append(parts, "original")
+	}
+	if len(f.media) > 0 {
+		parts = append(parts, "media="+f.media)
+	}
+	if f.include != nil {
+		parts = append(parts, "include="+f.include.String())
+	}
+	if f.exclude != nil {
+		parts = append(parts, "exclude="+f.exclude.String())
+	}
+	return strings.Join(parts, "&")
+}
+
+// accept post on client side, the server side filter may be unavailable or inexact
+func (f *contentFilter) accept(mblog *api.Mblog) bool {
+	if f.originalOnly && mblog.RetweetedStatus != nil {
+		return false
+	}
+	hasPicture := len(mblog.Pics) > 0 || (mblog.PicNum != nil && *mblog.PicNum > 0)
+	hasVideo := mblog.PageInfo != nil && mblog.PageInfo.Type == "video"
+	switch f.media {
+	case MEDIA_ANY:
+		if !hasPicture && !hasVideo {
+			return false
+		}
+	case MEDIA_PICTURE:
+		if !hasPicture {
+			return false
+		}
+	case MEDIA_VIDEO:
+		if !hasVideo {
+			return false
+		}
+	}
+	text := stringOf(mblog.RawText)
+	if len(text) == 0 {
+		text = stringOf(mblog.Text)
+	}
+	if f.include != nil && !f.include.MatchString(text) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.MatchString(text) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+const pictureMblog = `{"id": "11", "text": "<a>#旅行#</a> 风景", "raw_text": "#旅行# 风景", "pic_num": 2}`
+
+const videoMblog = `{"id": "12", "text": "广告 视频", "page_info": {"type": "video"}}`
+
+func TestContentFilter_Accept(t *testing.T) {
+	assert := assert.New(t)
+
+	original, repost := parseMblog(t, originalMblog), parseMblog(t, repostMblog)
+	picture, video := parseMblog(t, pictureMblog), parseMblog(t, videoMblog)
+	accepted := func(options ContentFilterOptions) (rt []bool) {
+		filter, err := options.contentFilter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, mblog := range []*api.Mblog{original, repost, picture, video} {
+			rt = append(rt, filter.accept(mblog))
+		}
+		return rt
+	}
+
+	assert.Equal([]bool{true, true, true, true}, accepted(ContentFilterOptions{}))
+	assert.Equal([]bool{true, false, true, true}, accepted(ContentFilterOptions{OriginalOnly: true}))
+	assert.Equal([]bool{false, false, true, true}, accepted(ContentFilterOptions{WithMedia: MEDIA_ANY}))
+	assert.Equal([]bool{false, false, true, false}, accepted(ContentFilterOptions{WithMedia: MEDIA_PICTURE}))
+	assert.Equal([]bool{false, false, false, true}, accepted(ContentFilterOptions{WithMedia: MEDIA_VIDEO}))
+	// raw text is matched if available, otherwise the text
+	assert.Equal([]bool{false, false, true, false}, accepted(ContentFilterOptions{Include: "^#旅行#"}))
+	assert.Equal([]bool{true, true, true, false}, accepted(ContentFilterOptions{Exclude: "广告"}))
+
+	_, err := (&ContentFilterOptions{WithMedia: "audio"}).contentFilter()
+	assert.NotNil(err)
+	_, err = (&ContentFilterOptions{Include: "("}).contentFilter()
+	assert.NotNil(err)
+}
+
+func TestContentFilter_ServerFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	serverFilter := func(options ContentFilterOptions) string {
+		filter, err := options.contentFilter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return filter.serverFilter()
+	}
+	assert.Equal("", serverFilter(ContentFilterOptions{}))
+	assert.Equal("", serverFilter(ContentFilterOptions{WithMedia: MEDIA_ANY, Include: "a"}))
+	assert.Equal(api.FILTER_ORIGINAL, serverFilter(ContentFilterOptions{OriginalOnly: true, WithMedia: MEDIA_PICTURE}))
+	assert.Equal(api.FILTER_PICTURE, serverFilter(ContentFilterOptions{WithMedia: MEDIA_PICTURE}))
+	assert.Equal(api.FILTER_VIDEO, serverFilter(ContentFilterOptions{WithMedia: MEDIA_VIDEO}))
+}
+
+func TestContentFilter_Signature(t *testing.T) {
+	assert := assert.New(t)
+
+	signature := func(options ContentFilterOptions) string {
+		filter, err := options.contentFilter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return filter.signature()
+	}
+	assert.Equal("", signature(ContentFilterOptions{}))
+	assert.Equal("original&media=picture&include=a&exclude=b", signature(ContentFilterOptions{
+		OriginalOnly: true,
+		WithMedia:    MEDIA_PICTURE,
+		Include:      "a",
+		Exclude:      "b",
+	}))
+
+	// the feeds read with different filters are checkpointed under different keys
+	r := &feedReader{}
+	r.feedKey = "weibo user:1"
+	r.filter, _ = (&ContentFilterOptions{}).contentFilter()
+	assert.Equal("weibo user:1", r.checkpointKey())
+	r.filter, _ = (&ContentFilterOptions{OriginalOnly: true}).contentFilter()
+	assert.Equal("weibo user:1?original", r.checkpointKey())
+}
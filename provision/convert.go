@@ -0,0 +1,340 @@
+package provision
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+)
+
+const KEY_WEIBO_HEADLINE_ARTICLE_TYPE = "WeiboHeadlineArticle"
+
+// article create time has no zone info, it is always in China Standard Time
+var weiboLocation = time.FixedZone("CST", 8*60*60)
+
+// idSet of emitted articles, could be shared by convertors in goroutines
+type idSet struct {
+	mu  sync.Mutex
+	ids map[model.ID]bool
+}
+
+func newIdSet() *idSet {
+	return &idSet{ids: map[model.ID]bool{}}
+}
+
+// add id to set, return false if it exists
+func (s *idSet) add(id model.ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ids[id] {
+		return false
+	}
+	s.ids[id] = true
+	return true
+}
+
+// weiboConvertor convert weibo api entities to archive articles,
+// each article will only be emitted once by the convertors sharing the same id set
+type weiboConvertor struct {
+	api       *api.WeiboAPI
+	convertor *md.Converter
+	emitted   *idSet
+	// attach raw json to ExtAttributes
+	attachRaw bool
+	// optional side store of raw json
+	rawStore RawStore
+	// extended profile of users, applied to the converted authors
+	details map[model.ID]*api.UserDetail
+	// retry of fetching the content linked by posts, e.g. poll or headline article
+	retry retryPolicy
+	// first failure of fetching the linked content, the partial articles are not returned
+	err error
+}
+
+func newWeiboConvertor(weiboAPI *api.WeiboAPI, emitted *idSet) *weiboConvertor {
+	return &weiboConvertor{
+		api:       weiboAPI,
+		convertor: md.NewConverter("", true, nil),
+		emitted:   emitted,
+		details:   map[model.ID]*api.UserDetail{},
+		retry:     defaultRetryPolicy,
+	}
+}
+
+// fail the conversion, only the first failure is kept
+func (c *weiboConvertor) fail(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// Err of fetching the linked content, nil if all posts have been fully converted
+func (c *weiboConvertor) Err() error {
+	return c.err
+}
+
+// unseen mark article as emitted, return false if it has been emitted before
+func (c *weiboConvertor) unseen(article *model.Article) bool {
+	return c.emitted.add(article.ID)
+}
+
+func (c *weiboConvertor) markdown(html string) *string {
+	content, err := c.convertor.ConvertString(html)
+	if err != nil {
+		log.Println("convert md failed", err)
+		return &html
+	}
+	return &content
+}
+
+// keepRaw json of entity by attaching it to the ext attributes or writing it to the raw store
+func (c *weiboConvertor) keepRaw(id model.ID, ext extAttributes, key string, raw []byte) {
+	if len(raw) == 0 {
+		return
+	}
+	if c.attachRaw {
+		ext[key] = string(raw)
+	}
+	if c.rawStore != nil {
+		if err := c.rawStore.Put(id, raw); err != nil {
+			log.Println("store raw json failed", err)
+		}
+	}
+}
+
+// convertMblog to article, and the articles it links to (headline article, retweeted post),
+// nil if the linked content failed to fetch, see 'Err'
+func (c *weiboConvertor) convertMblog(mblog *api.Mblog) (rt []*model.Article) {
+	ext := mblogExtAttributes(mblog)
+	article := &model.Article{
+		ID:            model.CreateID(KEY_WEIBO_ARTICLE_TYPE, stringOf(mblog.ID)),
+		Medias:        []*model.Media{},
+		ExtAttributes: ext,
+	}
+	c.keepRaw(article.ID, ext, EXT_RAW, mblog.Raw)
+	article.PublishDate = mblogPublishDate(mblog)
+	if mblog.Text != nil {
+		article.Content = c.markdown(*mblog.Text)
+	}
+	if mblog.User != nil {
+		article.Author = c.convertUser(mblog.User)
+	}
+	article.Medias = append(article.Medias, convertPics(mblog.AllPics())...)
+	article.Medias = append(article.Medias, convertVideo(mblog.PageInfo)...)
+	c.applyPoll(article, ext, mblog.PageInfo)
+
+	var related []*model.Article
+	if headline := c.fetchHeadlineArticle(mblog.PageInfo); headline != nil {
+		// the article of paid or restricted post is restricted as well
+		setAccessLevel(headline, AccessLevel(article))
+		article.References = append(article.References, &model.Reference{
+			Type:        RefTypeHeadlineArticle,
+			ReferenceId: string(headline.ID),
+		})
+		related = append(related, headline)
+	}
+	if mblog.RetweetedStatus != nil {
+		retweeted := c.convertRetweetedStatus(mblog.RetweetedStatus)
+		article.References = append(article.References, &model.Reference{
+			Type:        RefTypeRetweet,
+			ReferenceId: string(retweeted[0].ID),
+		})
+		related = append(related, retweeted...)
+	}
+	if c.err != nil {
+		return nil
+	}
+
+	for _, a := range append([]*model.Article{article}, related...) {
+		if c.unseen(a) {
+			rt = append(rt, a)
+		}
+	}
+	return rt
+}
+
+// convertRetweetedStatus to article, the first one is the original post,
+// a placeholder is returned if the original post has been deleted
+func (c *weiboConvertor) convertRetweetedStatus(status *api.RetweetedStatus) (rt []*model.Article) {
+	article := &model.Article{
+		ID:      model.CreateID(KEY_WEIBO_ARTICLE_TYPE, status.ID),
+		Content: c.markdown(status.Text),
+		Medias:  []*model.Media{},
+	}
+	if status.IsDeleted() {
+		// the audience of deleted post is unknown
+		article.ExtAttributes = extAttributes{EXT_DELETED: true, EXT_ACCESS_LEVEL: ACCESS_RESTRICTED}
+		return []*model.Article{article}
+	}
+	ext := retweetedExtAttributes(status)
+	article.ExtAttributes = ext
+	c.keepRaw(article.ID, ext, EXT_RAW, status.Raw)
+	if createAt, err := time.Parse(time.RubyDate, status.CreatedAt); err == nil {
+		article.PublishDate = &createAt
+	}
+	article.Author = c.convertUser(&status.User)
+	article.Medias = append(article.Medias, convertPics(status.Pics)...)
+	c.applyPoll(article, ext, &status.PageInfo)
+	rt = append(rt, article)
+	if headline := c.fetchHeadlineArticle(&status.PageInfo); headline != nil {
+		setAccessLevel(headline, AccessLevel(article))
+		article.References = append(article.References, &model.Reference{
+			Type:        RefTypeHeadlineArticle,
+			ReferenceId: string(headline.ID),
+		})
+		rt = append(rt, headline)
+	}
+	return rt
+}
+
+// convertUserArticle record of user, the id of article is the same as author
+func (c *weiboConvertor) convertUserArticle(user *api.User) *model.Article {
+	author := c.convertUser(user)
+	ext := extAttributes{EXT_ACCESS_LEVEL: ACCESS_PUBLIC}
+	for key, value := range author.ExtAttributes {
+		ext[key] = value
+	}
+	return &model.Article{
+		ID:            author.ID,
+		Type:          KEY_WEIBO_USER_TYPE,
+		Title:         &author.FullName,
+		Author:        author,
+		Content:       &user.Description,
+		ExtAttributes: ext,
+	}
+}
+
+func convertUserInfo(info *api.DataUserInfo) *model.Author {
+	return &model.Author{
+		ID:            model.CreateID(KEY_WEIBO_USER_TYPE, info.ID),
+		FullName:      info.ScreenName,
+		ExtAttributes: userInfoExtAttributes(info),
+	}
+}
+
+// mblogPublishDate parsed from created at, nil if absent or malformed
+func mblogPublishDate(mblog *api.Mblog) *time.Time {
+	if mblog.CreatedAt == nil {
+		return nil
+	}
+	createAt, err := time.Parse(time.RubyDate, *mblog.CreatedAt)
+	if err != nil {
+		return nil
+	}
+	return &createAt
+}
+
+func (c *weiboConvertor) convertUser(user *api.User) *model.Author {
+	ext := userExtAttributes(user)
+	author := &model.Author{
+		ID:            model.CreateID(KEY_WEIBO_USER_TYPE, user.ID),
+		FullName:      user.ScreenName,
+		ExtAttributes: ext,
+	}
+	c.keepRaw(author.ID, ext, EXT_USER_RAW, user.Raw)
+	if detail, found := c.details[author.ID]; found {
+		applyUserDetail(author, detail, time.Now())
+	}
+	return author
+}
+
+func convertPics(pics []api.Pic) (rt []*model.Media) {
+	imageType := "image/jpg"
+	for i := range pics {
+		rt = append(rt, &model.Media{
+			ID:           model.CreateID(KEY_WEIBO_RESOURCE_TYPE, pics[i].URL),
+			MimeType:     &imageType,
+			ExternalLink: &pics[i].URL,
+		})
+	}
+	return rt
+}
+
+// convertVideo of video page with its cover, nil if the page is not a video
+func convertVideo(pageInfo *api.PageInfo) (rt []*model.Media) {
+	if pageInfo == nil {
+		return nil
+	}
+	video := pageInfo.VideoURL()
+	if len(video) == 0 {
+		return nil
+	}
+	videoType := "video/mp4"
+	rt = append(rt, &model.Media{
+		ID:           model.CreateID(KEY_WEIBO_RESOURCE_TYPE, video),
+		MimeType:     &videoType,
+		ExternalLink: &video,
+	})
+	if cover := pageInfo.PagePic.URL; len(cover) > 0 {
+		imageType := "image/jpg"
+		rt = append(rt, &model.Media{
+			ID:           model.CreateID(KEY_WEIBO_RESOURCE_TYPE, cover),
+			MimeType:     &imageType,
+			ExternalLink: &cover,
+		})
+	}
+	return rt
+}
+
+// fetchHeadlineArticle for teaser post, return nil if the post is not a teaser or fetch failed,
+// the failure is kept in the convertor
+func (c *weiboConvertor) fetchHeadlineArticle(pageInfo *api.PageInfo) *model.Article {
+	articleId := api.GetArticleId(pageInfo)
+	if len(articleId) == 0 {
+		return nil
+	}
+	var headline *api.WeiboArticle
+	if err := c.retry.do(func() (err error) {
+		headline, err = c.api.GetArticle(articleId)
+		return err
+	}); err != nil {
+		c.fail(fmt.Errorf("fetch headline article '%s' failed: %w", articleId, err))
+		return nil
+	}
+	return c.convertHeadlineArticle(articleId, headline.Data)
+}
+
+func (c *weiboConvertor) convertHeadlineArticle(articleId string, data *api.ArticleData) *model.Article {
+	article := &model.Article{
+		ID:      model.CreateID(KEY_WEIBO_HEADLINE_ARTICLE_TYPE, articleId),
+		Type:    KEY_WEIBO_HEADLINE_ARTICLE_TYPE,
+		Title:   &data.Title,
+		Content: c.markdown(data.Content),
+		Medias:  []*model.Media{},
+	}
+	if publishAt, err := time.ParseInLocation("2006-01-02 15:04", data.CreateAt, weiboLocation); err == nil {
+		article.PublishDate = &publishAt
+	}
+	if data.Userinfo.Uid != 0 {
+		article.Author = &model.Author{
+			ID:       model.CreateID(KEY_WEIBO_USER_TYPE, data.Userinfo.Uid),
+			FullName: data.Userinfo.ScreenName,
+		}
+	}
+	imageType := "image/jpg"
+	images := data.Images()
+	if data.CoverImg != nil && len(data.CoverImg.Image.URL) > 0 {
+		images = append([]string{data.CoverImg.Image.URL}, images...)
+	}
+	for i := range images {
+		article.Medias = append(article.Medias, &model.Media{
+			ID:           model.CreateID(KEY_WEIBO_RESOURCE_TYPE, images[i]),
+			MimeType:     &imageType,
+			ExternalLink: &images[i],
+		})
+	}
+	return article
+}
+
+// stringOf optional field, the id must be hashed by value rather than by pointer
+func stringOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
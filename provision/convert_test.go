@@ -0,0 +1,111 @@
+package provision
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/imroc/req"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseMblog(t *testing.T, data string) *api.Mblog {
+	mblog := &api.Mblog{}
+	if err := json.Unmarshal([]byte(data), mblog); err != nil {
+		t.Fatal(err)
+	}
+	return mblog
+}
+
+const originalMblog = `{
+	"id": "4000000000000001",
+	"created_at": "Mon Oct 19 10:00:00 +0800 2026",
+	"text": "original post",
+	"user": {"id": 1, "screen_name": "alice"}
+}`
+
+const repostMblog = `{
+	"id": "4000000000000002",
+	"created_at": "Mon Oct 19 11:00:00 +0800 2026",
+	"text": "repost",
+	"user": {"id": 2, "screen_name": "bob"},
+	"retweeted_status": {
+		"id": "4000000000000001",
+		"created_at": "Mon Oct 19 10:00:00 +0800 2026",
+		"text": "original post",
+		"user": {"id": 1, "screen_name": "alice"}
+	}
+}`
+
+func TestConvertMblog_StableID(t *testing.T) {
+	assert := assert.New(t)
+
+	first := newWeiboConvertor(api.NewWeiboAPI(), newIdSet()).convertMblog(parseMblog(t, originalMblog))
+	second := newWeiboConvertor(api.NewWeiboAPI(), newIdSet()).convertMblog(parseMblog(t, originalMblog))
+	assert.Len(first, 1)
+	assert.Len(second, 1)
+	assert.Equal(first[0].ID, second[0].ID)
+}
+
+func TestConvertMblog_RepostDedup(t *testing.T) {
+	assert := assert.New(t)
+
+	c := newWeiboConvertor(api.NewWeiboAPI(), newIdSet())
+	reposted := c.convertMblog(parseMblog(t, repostMblog))
+	assert.Len(reposted, 2)
+	assert.Equal(RefTypeRetweet, reposted[0].References[0].Type)
+	assert.Equal(string(reposted[1].ID), reposted[0].References[0].ReferenceId)
+	// the original has been emitted with the repost
+	assert.Len(c.convertMblog(parseMblog(t, originalMblog)), 0)
+}
+
+// offlineTransport fails all requests, to test the failures of api calls without network
+type offlineTransport struct {
+	requests int
+}
+
+func (t *offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	t.requests++
+	return nil, errors.New("offline")
+}
+
+func offline(t *testing.T) *offlineTransport {
+	client := req.Client()
+	transport := &offlineTransport{}
+	req.SetClient(&http.Client{Transport: transport})
+	t.Cleanup(func() { req.SetClient(client) })
+	return transport
+}
+
+const pollMblog = `{
+	"id": "4000000000000003",
+	"text": "vote",
+	"page_info": {"type": "vote", "object_id": "2297:2019_1234567890"}
+}`
+
+const teaserMblog = `{
+	"id": "4000000000000004",
+	"text": "teaser",
+	"page_info": {"type": "article", "page_id": "2309404629234345640024"}
+}`
+
+func TestConvertMblog_FetchFailed(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, data := range []string{pollMblog, teaserMblog} {
+		transport := offline(t)
+		emitted := newIdSet()
+		c := newWeiboConvertor(api.NewWeiboAPI(), emitted)
+		c.retry = retryPolicy{attempts: 2, backoff: time.Millisecond}
+		mblog := parseMblog(t, data)
+		// the partial article is not returned, and the post is not marked as emitted
+		assert.Len(c.convertMblog(mblog), 0)
+		assert.NotNil(c.Err())
+		assert.Equal(2, transport.requests)
+		assert.True(emitted.add(model.CreateID(KEY_WEIBO_ARTICLE_TYPE, stringOf(mblog.ID))))
+	}
+}
@@ -0,0 +1,95 @@
+package provision
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ArchiveLife/core/adapter"
+)
+
+// date layouts accepted by 'Since' and 'Until' options
+var dateRangeLayouts = []string{"2006-01-02", time.RFC3339}
+
+// DateRangeOptions of weibo services, embedded in readers to archive posts of a given period
+type DateRangeOptions struct {
+	Since string
+	Until string
+}
+
+func createDateRangeOptions(order int) []*adapter.Option {
+	sinceLabel := "Since"
+	sinceDesc := "only archive posts published since this date (inclusive), e.g. '2021-01-01' or '2021-01-01T08:00:00+08:00'"
+	untilLabel := "Until"
+	untilDesc := "only archive posts published until this date (inclusive), e.g. '2021-12-31' or '2021-12-31T20:00:00+08:00'"
+	return []*adapter.Option{
+		{
+			Order:       order,
+			Name:        "Since",
+			Label:       &sinceLabel,
+			Description: &sinceDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       order + 1,
+			Name:        "Until",
+			Label:       &untilLabel,
+			Description: &untilDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+	}
+}
+
+// dateRange of posts, nil bound is unlimited
+type dateRange struct {
+	since *time.Time
+	// exclusive
+	until *time.Time
+}
+
+func (o *DateRangeOptions) dateRange() (rt dateRange, err error) {
+	if len(o.Since) > 0 {
+		since, _, err := parseDate(o.Since)
+		if err != nil {
+			return rt, err
+		}
+		rt.since = &since
+	}
+	if len(o.Until) > 0 {
+		until, dateOnly, err := parseDate(o.Until)
+		if err != nil {
+			return rt, err
+		}
+		if dateOnly {
+			until = until.AddDate(0, 0, 1)
+		} else {
+			until = until.Add(time.Second)
+		}
+		rt.until = &until
+	}
+	if rt.since != nil && rt.until != nil && !rt.since.Before(*rt.until) {
+		return rt, fmt.Errorf("'Since' %s is after 'Until' %s", o.Since, o.Until)
+	}
+	return rt, nil
+}
+
+func parseDate(value string) (time.Time, bool, error) {
+	for i, layout := range dateRangeLayouts {
+		if t, err := time.ParseInLocation(layout, value, weiboLocation); err == nil {
+			return t, i == 0, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid date '%s', should be like '2021-01-01'", value)
+}
+
+// tooNew post, published after the range
+func (d dateRange) tooNew(publishAt *time.Time) bool {
+	return d.until != nil && publishAt != nil && !publishAt.Before(*d.until)
+}
+
+// tooOld post, published before the range
+func (d dateRange) tooOld(publishAt *time.Time) bool {
+	return d.since != nil && publishAt != nil && publishAt.Before(*d.since)
+}
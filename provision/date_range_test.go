@@ -0,0 +1,104 @@
+package provision
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateRange(t *testing.T) {
+	assert := assert.New(t)
+
+	period, err := (&DateRangeOptions{Since: "2021-01-01", Until: "2021-01-31"}).dateRange()
+	assert.Nil(err)
+	// the dates are in China Standard Time
+	assert.Equal(time.Date(2020, 12, 31, 16, 0, 0, 0, time.UTC), period.since.UTC())
+	// the whole day of date-only 'Until' is included
+	assert.Equal(time.Date(2021, 1, 31, 16, 0, 0, 0, time.UTC), period.until.UTC())
+
+	at := func(value string) *time.Time {
+		t, _ := time.Parse(time.RubyDate, value)
+		return &t
+	}
+	assert.False(period.tooNew(at("Sun Jan 31 23:59:59 +0800 2021")))
+	assert.True(period.tooNew(at("Mon Feb 01 00:00:00 +0800 2021")))
+	assert.False(period.tooOld(at("Fri Jan 01 00:00:00 +0800 2021")))
+	assert.True(period.tooOld(at("Thu Dec 31 23:59:59 +0800 2020")))
+	// the post without date is kept
+	assert.False(period.tooNew(nil))
+	assert.False(period.tooOld(nil))
+
+	period, err = (&DateRangeOptions{Until: "2021-01-31T20:00:00+08:00"}).dateRange()
+	assert.Nil(err)
+	assert.Equal(time.Date(2021, 1, 31, 12, 0, 1, 0, time.UTC), period.until.UTC())
+
+	_, err = (&DateRangeOptions{Since: "2021-02-01", Until: "2021-01-31"}).dateRange()
+	assert.NotNil(err)
+	_, err = (&DateRangeOptions{Since: "2021/01/01"}).dateRange()
+	assert.NotNil(err)
+}
+
+func TestFeedReader_PinnedOldPost(t *testing.T) {
+	assert := assert.New(t)
+
+	dated := func(id int, createdAt string, pinned bool) *api.Mblog {
+		mblog := mblogOf(id)
+		mblog.CreatedAt = &createdAt
+		if pinned {
+			isTop := int64(1)
+			mblog.IsTop = &isTop
+		}
+		return mblog
+	}
+	var read []int
+	readPage := func(page int) ([]*api.Mblog, bool, error) {
+		read = append(read, page)
+		switch page {
+		case 1:
+			return []*api.Mblog{
+				dated(1, "Fri Jan 01 10:00:00 +0800 2016", true),
+				dated(5, "Mon Oct 19 10:00:00 +0800 2026", false),
+			}, true, nil
+		case 2:
+			return []*api.Mblog{
+				dated(4, "Sun Oct 18 10:00:00 +0800 2026", false),
+				dated(3, "Sun Jan 31 10:00:00 +0800 2021", false),
+				dated(2, "Sat Jan 30 10:00:00 +0800 2021", false),
+			}, true, nil
+		}
+		return nil, false, nil
+	}
+	r := &feedReader{DateRangeOptions: DateRangeOptions{Since: "2026-01-01"}}
+	ids := readFeed(t, r, readPage)
+	// the pinned old post is skipped, the crawl stops at the first old post in order
+	assert.Equal(postIDs(5, 4), ids)
+	assert.Equal([]int{1, 2}, read)
+}
+
+func TestActivityReader_DateRange(t *testing.T) {
+	assert := assert.New(t)
+
+	period, err := (&DateRangeOptions{Since: "2021-01-01", Until: "2021-01-31"}).dateRange()
+	assert.Nil(err)
+	r := &activityReader{period: period, convertor: newWeiboConvertor(api.NewWeiboAPI(), newIdSet())}
+	var converted []string
+	dated := func(id string, date time.Time) activity {
+		return activity{id: id, date: &date, convert: func() []*model.Article {
+			converted = append(converted, id)
+			return nil
+		}}
+	}
+	r.convertActivities([]activity{
+		dated("4", time.Date(2021, 2, 1, 0, 0, 0, 0, weiboLocation)),
+		{id: "3", convert: func() []*model.Article { converted = append(converted, "3"); return nil }},
+		dated("2", time.Date(2021, 1, 15, 0, 0, 0, 0, weiboLocation)),
+		dated("1", time.Date(2020, 12, 31, 0, 0, 0, 0, weiboLocation)),
+	})
+	// the activity without date is kept
+	assert.Equal([]string{"3", "2"}, converted)
+	assert.Equal("3", r.newestID)
+	assert.True(r.stopped)
+}
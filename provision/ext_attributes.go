@@ -0,0 +1,222 @@
+package provision
+
+import (
+	"time"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// keys of model.Article.ExtAttributes, the key set is stable so that
+// downstream tools could filter and analyze archived posts without re-fetching,
+// every key is present in each post and the value is nil if weibo omits it,
+// except the optional keys marked with 'only'
+const (
+	// EXT_SOURCE client of post, string, e.g. 'iPhone客户端'
+	EXT_SOURCE = "source"
+	// EXT_REPOSTS_COUNT int64
+	EXT_REPOSTS_COUNT = "reposts_count"
+	// EXT_COMMENTS_COUNT int64
+	EXT_COMMENTS_COUNT = "comments_count"
+	// EXT_ATTITUDES_COUNT (likes) int64
+	EXT_ATTITUDES_COUNT = "attitudes_count"
+	// EXT_BID short id of post used in web url, string
+	EXT_BID = "bid"
+	// EXT_MID message id of post, string
+	EXT_MID = "mid"
+	// EXT_IS_TOP pinned post on user page, bool
+	EXT_IS_TOP = "is_top"
+	// EXT_VISIBLE_TYPE raw visible type of post, int64
+	EXT_VISIBLE_TYPE = "visible_type"
+	// EXT_VISIBLE_LIST_ID friend group the post visible to, int64
+	EXT_VISIBLE_LIST_ID = "visible_list_id"
+	// EXT_ACCESS_LEVEL normalized audience of article, string, one of ACCESS_*, set on every article
+	EXT_ACCESS_LEVEL = "access_level"
+	// EXT_REGION_NAME location of ip when posting, string, e.g. '发布于 北京'
+	EXT_REGION_NAME = "region_name"
+	// EXT_PIC_NUM number of pictures, int64
+	EXT_PIC_NUM = "pic_num"
+	// EXT_EDIT_COUNT times of editing, int64
+	EXT_EDIT_COUNT = "edit_count"
+	// EXT_DELETED the article is a placeholder of deleted post, bool, only on the placeholder which has no other keys of post
+	EXT_DELETED = "deleted"
+	// EXT_RAW raw json of post as returned by weibo, string, only when raw json is kept
+	EXT_RAW = "raw"
+	// EXT_POLL_ID id of poll in post, string, only when the post has a poll
+	EXT_POLL_ID = "poll_id"
+	// EXT_POLL_QUESTION string, only when the post has a poll
+	EXT_POLL_QUESTION = "poll_question"
+	// EXT_POLL_PARTICIPANTS count of participants, int64, only when the results are fetched
+	EXT_POLL_PARTICIPANTS = "poll_participants"
+	// EXT_POLL_OPTIONS list of option and votes, only when the results are fetched
+	EXT_POLL_OPTIONS = "poll_options"
+	// EXT_POLL_FINAL the poll has expired and the results are final, bool, only when the results are fetched
+	EXT_POLL_FINAL = "poll_final"
+)
+
+// keys of model.Author.ExtAttributes, every key is present and the value is nil
+// if weibo omits it, except the optional keys marked with 'only'
+const (
+	// EXT_USER_VERIFIED bool
+	EXT_USER_VERIFIED = "verified"
+	// EXT_USER_VERIFIED_TYPE int64
+	EXT_USER_VERIFIED_TYPE = "verified_type"
+	// EXT_USER_VERIFIED_REASON string
+	EXT_USER_VERIFIED_REASON = "verified_reason"
+	// EXT_USER_DESCRIPTION string
+	EXT_USER_DESCRIPTION = "description"
+	// EXT_USER_GENDER string, 'f' or 'm'
+	EXT_USER_GENDER = "gender"
+	// EXT_USER_FOLLOWERS_COUNT int64
+	EXT_USER_FOLLOWERS_COUNT = "followers_count"
+	// EXT_USER_FOLLOW_COUNT int64
+	EXT_USER_FOLLOW_COUNT = "follow_count"
+	// EXT_USER_STATUSES_COUNT int64
+	EXT_USER_STATUSES_COUNT = "statuses_count"
+	// EXT_USER_AVATAR url of avatar, string
+	EXT_USER_AVATAR = "avatar"
+	// EXT_USER_PROFILE_URL string
+	EXT_USER_PROFILE_URL = "profile_url"
+	// EXT_USER_RAW raw json of user as returned by weibo, string, only when raw json is kept
+	EXT_USER_RAW = "raw"
+	// EXT_USER_BIRTHDAY string, e.g. '1990-01-01 摩羯座', only when extended profile is read
+	EXT_USER_BIRTHDAY = "birthday"
+	// EXT_USER_EDUCATION string, e.g. '大学: 北京大学; 高中: 北京四中', only when extended profile is read
+	EXT_USER_EDUCATION = "education"
+	// EXT_USER_COMPANY string, only when extended profile is read
+	EXT_USER_COMPANY = "company"
+	// EXT_USER_REGISTERED_AT date of registration, string, only when extended profile is read
+	EXT_USER_REGISTERED_AT = "registered_at"
+	// EXT_USER_CREDIT_LEVEL (阳光信用) string, only when extended profile is read
+	EXT_USER_CREDIT_LEVEL = "credit_level"
+)
+
+type extAttributes map[string]interface{}
+
+// setString of key, nil if the value is absent or empty so that the key set is stable
+func (e extAttributes) setString(key string, value *string) {
+	if value != nil && len(*value) > 0 {
+		e[key] = *value
+		return
+	}
+	e[key] = nil
+}
+
+// setInt of key, nil if the value is absent
+func (e extAttributes) setInt(key string, value *int64) {
+	if value != nil {
+		e[key] = *value
+		return
+	}
+	e[key] = nil
+}
+
+func mblogExtAttributes(mblog *api.Mblog) extAttributes {
+	e := extAttributes{}
+	e.setString(EXT_SOURCE, &mblog.Source)
+	e.setInt(EXT_REPOSTS_COUNT, mblog.RepostsCount)
+	e.setInt(EXT_COMMENTS_COUNT, mblog.CommentsCount)
+	e.setInt(EXT_ATTITUDES_COUNT, mblog.AttitudesCount)
+	e.setString(EXT_BID, mblog.Bid)
+	e.setString(EXT_MID, mblog.Mid)
+	e[EXT_IS_TOP] = mblog.IsTop != nil && *mblog.IsTop == 1
+	e[EXT_VISIBLE_TYPE] = nil
+	e[EXT_VISIBLE_LIST_ID] = nil
+	if mblog.Visible != nil {
+		e[EXT_VISIBLE_TYPE] = mblog.Visible.Type
+		e[EXT_VISIBLE_LIST_ID] = mblog.Visible.ListID
+	}
+	e[EXT_ACCESS_LEVEL] = mblogAccessLevel(mblog)
+	e.setString(EXT_REGION_NAME, mblog.RegionName)
+	e.setInt(EXT_PIC_NUM, mblog.PicNum)
+	e.setInt(EXT_EDIT_COUNT, mblog.EditCount)
+	return e
+}
+
+func retweetedExtAttributes(status *api.RetweetedStatus) extAttributes {
+	e := extAttributes{
+		EXT_REPOSTS_COUNT:   status.RepostsCount,
+		EXT_COMMENTS_COUNT:  status.CommentsCount,
+		EXT_ATTITUDES_COUNT: status.AttitudesCount,
+		EXT_IS_TOP:          false,
+		EXT_VISIBLE_TYPE:    status.Visible.Type,
+		EXT_VISIBLE_LIST_ID: status.Visible.ListID,
+		EXT_ACCESS_LEVEL:    accessLevel(&status.Visible, status.IsPaid, status.ContentAuth != 0),
+		EXT_PIC_NUM:         status.PicNum,
+	}
+	e.setString(EXT_SOURCE, &status.Source)
+	e.setString(EXT_BID, &status.Bid)
+	e.setString(EXT_MID, &status.Mid)
+	e.setString(EXT_REGION_NAME, status.RegionName)
+	e.setInt(EXT_EDIT_COUNT, status.EditCount)
+	return e
+}
+
+func userExtAttributes(user *api.User) extAttributes {
+	e := extAttributes{
+		EXT_USER_VERIFIED:        user.Verified,
+		EXT_USER_VERIFIED_TYPE:   user.VerifiedType,
+		EXT_USER_FOLLOWERS_COUNT: user.FollowersCount,
+		EXT_USER_FOLLOW_COUNT:    user.FollowCount,
+	}
+	e.setString(EXT_USER_VERIFIED_REASON, &user.VerifiedReason)
+	e.setString(EXT_USER_DESCRIPTION, &user.Description)
+	gender := string(user.Gender)
+	e.setString(EXT_USER_GENDER, &gender)
+	e.setInt(EXT_USER_STATUSES_COUNT, user.StatusesCount)
+	e.setString(EXT_USER_AVATAR, &user.AvatarHD)
+	if e[EXT_USER_AVATAR] == nil {
+		e.setString(EXT_USER_AVATAR, &user.ProfileImageURL)
+	}
+	e.setString(EXT_USER_PROFILE_URL, &user.ProfileURL)
+	return e
+}
+
+func userInfoExtAttributes(info *api.DataUserInfo) extAttributes {
+	e := extAttributes{
+		EXT_USER_VERIFIED:        info.Verified,
+		EXT_USER_VERIFIED_TYPE:   info.VerifiedType,
+		EXT_USER_FOLLOWERS_COUNT: info.FollowersCount,
+		EXT_USER_FOLLOW_COUNT:    info.FollowCount,
+		EXT_USER_STATUSES_COUNT:  info.StatusesCount,
+	}
+	e.setString(EXT_USER_VERIFIED_REASON, &info.VerifiedReason)
+	e.setString(EXT_USER_DESCRIPTION, &info.Description)
+	e.setString(EXT_USER_GENDER, &info.Gender)
+	e.setString(EXT_USER_AVATAR, &info.AvatarHD)
+	if e[EXT_USER_AVATAR] == nil {
+		e.setString(EXT_USER_AVATAR, &info.ProfileImageURL)
+	}
+	e.setString(EXT_USER_PROFILE_URL, &info.ProfileURL)
+	return e
+}
+
+// applyUserDetail of extended profile to author, the items hidden by user are skipped
+func applyUserDetail(author *model.Author, detail *api.UserDetail, now time.Time) {
+	if author.ExtAttributes == nil {
+		author.ExtAttributes = map[string]interface{}{}
+	}
+	e := extAttributes(author.ExtAttributes)
+	if location := detail.Location(); len(location) > 0 {
+		author.Address = &location
+	}
+	birthday := detail.Birthday()
+	if len(birthday) >= 10 {
+		if born, err := time.Parse("2006-01-02", birthday[:10]); err == nil && born.Year() > 1900 {
+			age := now.Year() - born.Year()
+			if now.YearDay() < born.YearDay() {
+				age--
+			}
+			author.Age = &age
+		}
+	}
+	e.setString(EXT_USER_BIRTHDAY, &birthday)
+	education := detail.Education()
+	e.setString(EXT_USER_EDUCATION, &education)
+	company := detail.Company()
+	e.setString(EXT_USER_COMPANY, &company)
+	registeredAt := detail.RegisteredAt()
+	e.setString(EXT_USER_REGISTERED_AT, &registeredAt)
+	credit := detail.CreditLevel()
+	e.setString(EXT_USER_CREDIT_LEVEL, &credit)
+}
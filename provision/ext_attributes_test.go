@@ -0,0 +1,85 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+var mblogKeys = []string{
+	EXT_SOURCE, EXT_REPOSTS_COUNT, EXT_COMMENTS_COUNT, EXT_ATTITUDES_COUNT, EXT_BID, EXT_MID,
+	EXT_IS_TOP, EXT_VISIBLE_TYPE, EXT_VISIBLE_LIST_ID, EXT_ACCESS_LEVEL, EXT_REGION_NAME,
+	EXT_PIC_NUM, EXT_EDIT_COUNT,
+}
+
+var userKeys = []string{
+	EXT_USER_VERIFIED, EXT_USER_VERIFIED_TYPE, EXT_USER_VERIFIED_REASON, EXT_USER_DESCRIPTION,
+	EXT_USER_GENDER, EXT_USER_FOLLOWERS_COUNT, EXT_USER_FOLLOW_COUNT, EXT_USER_STATUSES_COUNT,
+	EXT_USER_AVATAR, EXT_USER_PROFILE_URL,
+}
+
+func assertKeys(assert *assert.Assertions, keys []string, e extAttributes) {
+	assert.Len(e, len(keys))
+	for _, key := range keys {
+		assert.Contains(e, key)
+	}
+}
+
+func TestMblogExtAttributes(t *testing.T) {
+	assert := assert.New(t)
+
+	// the keys are present even if weibo omits the fields
+	empty := mblogExtAttributes(&api.Mblog{})
+	assertKeys(assert, mblogKeys, empty)
+	assert.Nil(empty[EXT_SOURCE])
+	assert.Nil(empty[EXT_REPOSTS_COUNT])
+	assert.Nil(empty[EXT_VISIBLE_TYPE])
+	assert.Equal(false, empty[EXT_IS_TOP])
+
+	mblog := parseMblog(t, `{
+		"id": "1",
+		"source": "iPhone客户端",
+		"reposts_count": 3,
+		"isTop": 1,
+		"visible": {"type": 3, "list_id": 42},
+		"region_name": "发布于 北京"
+	}`)
+	e := mblogExtAttributes(mblog)
+	assertKeys(assert, mblogKeys, e)
+	assert.Equal("iPhone客户端", e[EXT_SOURCE])
+	assert.Equal(int64(3), e[EXT_REPOSTS_COUNT])
+	assert.Equal(true, e[EXT_IS_TOP])
+	assert.Equal(int64(3), e[EXT_VISIBLE_TYPE])
+	assert.Equal(int64(42), e[EXT_VISIBLE_LIST_ID])
+	assert.Equal("发布于 北京", e[EXT_REGION_NAME])
+}
+
+func TestRetweetedExtAttributes(t *testing.T) {
+	assert := assert.New(t)
+
+	e := retweetedExtAttributes(&api.RetweetedStatus{Source: "微博 weibo.com", RepostsCount: 5})
+	assertKeys(assert, mblogKeys, e)
+	assert.Equal("微博 weibo.com", e[EXT_SOURCE])
+	assert.Equal(int64(5), e[EXT_REPOSTS_COUNT])
+	assert.Nil(e[EXT_BID])
+	assert.Nil(e[EXT_EDIT_COUNT])
+}
+
+func TestUserExtAttributes(t *testing.T) {
+	assert := assert.New(t)
+
+	e := userExtAttributes(&api.User{FollowersCount: 10, ProfileImageURL: "https://tvax1.sinaimg.cn/small.jpg"})
+	assertKeys(assert, userKeys, e)
+	assert.Equal(int64(10), e[EXT_USER_FOLLOWERS_COUNT])
+	assert.Nil(e[EXT_USER_STATUSES_COUNT])
+	assert.Nil(e[EXT_USER_DESCRIPTION])
+	// fallback to the small avatar
+	assert.Equal("https://tvax1.sinaimg.cn/small.jpg", e[EXT_USER_AVATAR])
+
+	info := userInfoExtAttributes(&api.DataUserInfo{Gender: "f", AvatarHD: "https://tvax1.sinaimg.cn/large.jpg"})
+	assertKeys(assert, userKeys, info)
+	assert.Equal("f", info[EXT_USER_GENDER])
+	assert.Equal("https://tvax1.sinaimg.cn/large.jpg", info[EXT_USER_AVATAR])
+	assert.Nil(info[EXT_USER_VERIFIED_REASON])
+}
@@ -0,0 +1,79 @@
+package provision
+
+import (
+	"time"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_FAVORITES_CHECKPOINT = "weibo favorites:"
+
+// EXT_FAVORITED_AT time of adding the post to favorites, string in RFC3339
+const EXT_FAVORITED_AT = "favorited_at"
+
+func createFavoritesWeiboService() adapter.ArchiveService {
+	return newWeiboServiceWrapper(
+		"weibo favorites",
+		"get all favorite weibo of the logged-in account, with the time of favorite",
+		&FavoritesWeiboReader{},
+		createActivityOptions(0)...,
+	)
+}
+
+// FavoritesWeiboReader of favorites, newest favorite first
+type FavoritesWeiboReader struct {
+	activityReader
+}
+
+func (r *FavoritesWeiboReader) Init() error {
+	r.unorderedIds = true
+	return r.initActivities("favorites", KEY_FAVORITES_CHECKPOINT, r.readFavorites)
+}
+
+func (r *FavoritesWeiboReader) readFavorites(page int) (rt []activity, err error) {
+	favorites, err := r.api.GetFavorites(r.CookieSub, r.uid, page)
+	if err != nil || favorites.Ok != 1 {
+		return nil, err
+	}
+	for _, mblog := range favorites.Data {
+		rt = append(rt, r.favoriteActivity(mblog))
+	}
+	return rt, nil
+}
+
+// favoriteActivity identified by the post id, favorites are ordered by the time of favorite
+// rather than the post id
+func (r *FavoritesWeiboReader) favoriteActivity(mblog *api.Mblog) activity {
+	return activity{
+		id:      stringOf(mblog.ID),
+		date:    favoritedDate(mblog),
+		convert: func() []*model.Article { return r.convertFavorite(mblog) },
+	}
+}
+
+// convertFavorite post, the time of favorite is kept in the post article
+func (r *FavoritesWeiboReader) convertFavorite(mblog *api.Mblog) []*model.Article {
+	articles := r.convertor.convertMblog(mblog)
+	articleID := model.CreateID(KEY_WEIBO_ARTICLE_TYPE, stringOf(mblog.ID))
+	for _, article := range articles {
+		if article.ID == articleID {
+			if favoritedAt := favoritedDate(mblog); favoritedAt != nil {
+				article.ExtAttributes[EXT_FAVORITED_AT] = favoritedAt.Format(time.RFC3339)
+			}
+		}
+	}
+	return articles
+}
+
+func favoritedDate(mblog *api.Mblog) *time.Time {
+	if mblog.FavoritedTime == nil {
+		return nil
+	}
+	favoritedAt, err := time.Parse(time.RubyDate, *mblog.FavoritedTime)
+	if err != nil {
+		return nil
+	}
+	return &favoritedAt
+}
@@ -0,0 +1,265 @@
+package provision
+
+import (
+	"fmt"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// pageReader of feed, page starts from 1, return the posts of page and whether there are more pages
+type pageReader func(page int) (mblogs []*api.Mblog, more bool, err error)
+
+// cardsPage of posts in the cards of list page, weibo responds 'ok: 0' after the last page
+func cardsPage(index *api.WeiboUserListPageIndex, err error) ([]*api.Mblog, bool, error) {
+	if err != nil {
+		return nil, false, err
+	}
+	if index.Ok != 1 || len(index.Data.Cards) == 0 {
+		return nil, false, nil
+	}
+	return extractMblogs(index.Data.Cards), true, nil
+}
+
+// feedReader read the posts of a paged feed, e.g. user posts or search results,
+// with checkpoints, date range and content filter
+type feedReader struct {
+	RawOptions
+	CheckpointOptions
+	DateRangeOptions
+	ContentFilterOptions
+	currentPage int
+	tmp         []*model.Article
+	// articles of current page other than posts, e.g. photos not posted, emitted after the posts
+	pending   []*model.Article
+	api       *api.WeiboAPI
+	convertor *weiboConvertor
+	// api and emitted articles shared with other readers, e.g. in multi-user service
+	sharedAPI     *api.WeiboAPI
+	sharedEmitted *idSet
+	retry         retryPolicy
+	err           error
+	// name of feed in errors
+	name string
+	// key of feed in checkpoint store, without the content filter
+	feedKey  string
+	readPage pageReader
+	// posts of feed are not ordered by time, e.g. hot search results, which could not be
+	// read incrementally. the posts of other feeds are ordered from newest to oldest, except the pinned ones
+	unordered bool
+	// the feed could not be resumed from a page, e.g. it is paged by the since id
+	// of previous page, so the cursor is not checkpointed
+	noCursor    bool
+	checkpoints CheckpointStore
+	// checkpoint of previous runs, it will be updated along reading
+	checkpoint *Checkpoint
+	// newest archived post id of previous runs, empty if no checkpoint
+	archivedID string
+	// reached the archived posts in incremental mode
+	reachedArchived bool
+	// newest post id of this run
+	newestID string
+	stopped  bool
+	period   dateRange
+	filter   *contentFilter
+}
+
+// initFeed named for errors, the checkpoint of feed is stored under feedKey
+func (r *feedReader) initFeed(name string, feedKey string, readPage pageReader) error {
+	r.api = r.sharedAPI
+	if r.api == nil {
+		r.api = api.NewWeiboAPI()
+	}
+	emitted := r.sharedEmitted
+	if emitted == nil {
+		emitted = newIdSet()
+	}
+	r.convertor = newWeiboConvertor(r.api, emitted)
+	r.currentPage = 0
+	r.tmp = nil
+	r.pending = nil
+	r.retry = defaultRetryPolicy
+	r.err = nil
+	r.name = name
+	r.feedKey = feedKey
+	r.readPage = readPage
+	r.reachedArchived = false
+	r.newestID = ""
+	r.stopped = false
+	// the archived posts could not be told from the new ones by id
+	if r.unordered && r.Incremental {
+		return fmt.Errorf("%s is not ordered by time, which could not be read incrementally", name)
+	}
+	period, err := r.DateRangeOptions.dateRange()
+	if err != nil {
+		return err
+	}
+	r.period = period
+	if r.filter, err = r.ContentFilterOptions.contentFilter(); err != nil {
+		return err
+	}
+	if err := r.initCheckpoint(); err != nil {
+		return err
+	}
+	return r.RawOptions.apply(r.convertor)
+}
+
+func (r *feedReader) initCheckpoint() (err error) {
+	r.checkpoint = &Checkpoint{}
+	r.archivedID = ""
+	if r.checkpoints, err = r.CheckpointOptions.store(); err != nil || r.checkpoints == nil {
+		return err
+	}
+	checkpoint, err := r.checkpoints.Load(r.checkpointKey())
+	if err != nil {
+		return err
+	}
+	if checkpoint != nil {
+		r.checkpoint = checkpoint
+		r.archivedID = checkpoint.NewestID
+	}
+	if r.noCursor {
+		r.checkpoint.Cursor = 0
+	}
+	return nil
+}
+
+// inHead of feed, before reaching the archived posts in incremental mode
+func (r *feedReader) inHead() bool {
+	return r.Incremental && len(r.archivedID) > 0 && !r.reachedArchived
+}
+
+// saveCheckpoint with the cursor of next page, the history before the cursor has been consumed
+func (r *feedReader) saveCheckpoint(cursor int) error {
+	if r.checkpoints == nil {
+		return nil
+	}
+	if isNewer(r.newestID, r.checkpoint.NewestID) {
+		r.checkpoint.NewestID = r.newestID
+	}
+	if r.noCursor {
+		cursor = 0
+	}
+	r.checkpoint.Cursor = cursor
+	return r.checkpoints.Save(r.checkpointKey(), r.checkpoint)
+}
+
+// checkpointKey of feed, posts read with content filter are checkpointed separately
+func (r *feedReader) checkpointKey() string {
+	if signature := r.filter.signature(); len(signature) > 0 {
+		return r.feedKey + "?" + signature
+	}
+	return r.feedKey
+}
+
+// reachArchived posts in incremental mode, resume the unfinished history or stop
+func (r *feedReader) reachArchived() {
+	r.reachedArchived = true
+	if r.checkpoint.Cursor > 0 {
+		r.currentPage = r.checkpoint.Cursor - 1
+	} else {
+		r.stopped = true
+	}
+	if err := r.saveCheckpoint(r.checkpoint.Cursor); err != nil {
+		r.err = err
+		r.stopped = true
+	}
+}
+
+// stopBeforeRange of dates, the posts newer than the range have been read, and the posts older
+// than the range are left to the run without date range
+func (r *feedReader) stopBeforeRange() {
+	r.stopped = true
+	// the unfinished history is kept if stopped in head, otherwise resumed from current page
+	cursor := r.checkpoint.Cursor
+	if !r.inHead() {
+		cursor = r.currentPage
+	}
+	if err := r.saveCheckpoint(cursor); err != nil {
+		r.err = err
+	}
+}
+
+func (r *feedReader) Next() (*model.Article, bool) {
+	// pages may contain no new article, e.g. all posts have been emitted as retweeted
+	for len(r.tmp) == 0 {
+		if r.stopped {
+			return nil, false
+		}
+		// the articles of current page have been consumed, and the head pages are not checkpointed
+		if r.currentPage > 0 && !r.inHead() {
+			if err := r.saveCheckpoint(r.currentPage + 1); err != nil {
+				r.err = err
+				return nil, false
+			}
+		}
+		r.currentPage++
+		var mblogs []*api.Mblog
+		var more bool
+		err := r.retry.do(func() (err error) {
+			mblogs, more, err = r.readPage(r.currentPage)
+			return err
+		})
+		if err != nil {
+			r.err = fmt.Errorf("read page %d of %s failed: %w", r.currentPage, r.name, err)
+			return nil, false
+		}
+		if !more {
+			r.err = r.saveCheckpoint(0)
+			return nil, false
+		}
+		r.tmp = append(r.convertPageToArticles(mblogs), r.pending...)
+		r.pending = nil
+	}
+	rt := r.tmp[0]
+	r.tmp = r.tmp[1:]
+	return rt, true
+}
+
+// Err stopped reading, nil if all posts of feed have been read
+func (r *feedReader) Err() error {
+	return r.err
+}
+
+func (r *feedReader) convertPageToArticles(mblogs []*api.Mblog) (rt []*model.Article) {
+	for _, mblog := range mblogs {
+		id := stringOf(mblog.ID)
+		// pinned post is out of order, so as all posts of unordered feed
+		outOfOrder := r.unordered || (mblog.IsTop != nil && *mblog.IsTop == 1)
+		if r.inHead() && !isNewer(id, r.archivedID) {
+			// the following posts are older than archived ones
+			if outOfOrder {
+				continue
+			}
+			r.reachArchived()
+			break
+		}
+		publishAt := mblogPublishDate(mblog)
+		if r.period.tooNew(publishAt) {
+			continue
+		}
+		if r.period.tooOld(publishAt) {
+			// the following posts are older than the range
+			if outOfOrder {
+				continue
+			}
+			r.stopBeforeRange()
+			break
+		}
+		if isNewer(id, r.newestID) {
+			r.newestID = id
+		}
+		if !r.filter.accept(mblog) {
+			continue
+		}
+		articles := r.convertor.convertMblog(mblog)
+		// the post is left to the next run, without saving the checkpoint
+		if err := r.convertor.Err(); err != nil {
+			r.err = fmt.Errorf("convert post '%s' of %s failed: %w", id, r.name, err)
+			r.stopped = true
+			break
+		}
+		rt = append(rt, articles...)
+	}
+	return rt
+}
@@ -0,0 +1,124 @@
+package provision
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubFeedReader of pages, the page reads fail with the errors in order, nil for success
+type stubFeedReader struct {
+	feedReader
+	pages  [][]int
+	errors []error
+	calls  int
+}
+
+func (r *stubFeedReader) Init() error {
+	read := []int{}
+	pages := stubPages(&read, r.pages...)
+	if err := r.initFeed("stub", "stub:1", func(page int) ([]*api.Mblog, bool, error) {
+		r.calls++
+		if len(r.errors) > 0 {
+			err := r.errors[0]
+			r.errors = r.errors[1:]
+			if err != nil {
+				return nil, false, err
+			}
+		}
+		return pages(page)
+	}); err != nil {
+		return err
+	}
+	r.retry = retryPolicy{attempts: 3, backoff: time.Millisecond}
+	return nil
+}
+
+func TestFeedReader_RetryTransient(t *testing.T) {
+	assert := assert.New(t)
+
+	serverError := &api.StatusError{Endpoint: "stub", StatusCode: 500}
+	r := &stubFeedReader{pages: [][]int{{2, 1}}, errors: []error{serverError, serverError}}
+	assert.Nil(r.Init())
+	var ids []model.ID
+	for {
+		article, ok := r.Next()
+		if !ok {
+			break
+		}
+		ids = append(ids, article.ID)
+	}
+	assert.Nil(r.Err())
+	assert.Equal(postIDs(2, 1), ids)
+	// two failures of first page, then the first and the empty second page
+	assert.Equal(4, r.calls)
+}
+
+func TestFeedReader_GiveUp(t *testing.T) {
+	assert := assert.New(t)
+
+	// terminal error is not retried
+	notFound := &api.StatusError{Endpoint: "stub", StatusCode: 404}
+	r := &stubFeedReader{pages: [][]int{{1}}, errors: []error{notFound}}
+	assert.Nil(r.Init())
+	_, ok := r.Next()
+	assert.False(ok)
+	assert.True(errors.Is(r.Err(), notFound))
+	assert.Equal(1, r.calls)
+
+	// transient error is retried up to the attempts of policy
+	serverError := &api.StatusError{Endpoint: "stub", StatusCode: 502}
+	r = &stubFeedReader{pages: [][]int{{1}}, errors: []error{serverError, serverError, serverError}}
+	assert.Nil(r.Init())
+	_, ok = r.Next()
+	assert.False(ok)
+	assert.True(errors.Is(r.Err(), serverError))
+	assert.Equal(3, r.calls)
+}
+
+func TestWeiboServiceWrapper_Err(t *testing.T) {
+	assert := assert.New(t)
+
+	notFound := &api.StatusError{Endpoint: "stub", StatusCode: 404}
+	reader := &stubFeedReader{pages: [][]int{{1}}, errors: []error{notFound}}
+	err := newWeiboServiceWrapper("stub", "stub feed", reader).Run(func(*model.Article) {})
+	assert.True(errors.Is(err, notFound))
+
+	var consumed []model.ID
+	reader = &stubFeedReader{pages: [][]int{{2, 1}}}
+	assert.Nil(newWeiboServiceWrapper("stub", "stub feed", reader).Run(func(article *model.Article) {
+		consumed = append(consumed, article.ID)
+	}))
+	assert.Equal(postIDs(2, 1), consumed)
+}
+
+func TestFeedReader_ConvertFailed(t *testing.T) {
+	assert := assert.New(t)
+
+	offline(t)
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	r := &feedReader{CheckpointOptions: CheckpointOptions{Incremental: true, CheckpointFile: path}}
+	assert.Nil(r.initFeed("stub", "stub:1", func(page int) ([]*api.Mblog, bool, error) {
+		return []*api.Mblog{mblogOf(5), parseMblog(t, pollMblog), mblogOf(4)}, true, nil
+	}))
+	r.convertor.retry = retryPolicy{attempts: 1}
+	var ids []model.ID
+	for {
+		article, ok := r.Next()
+		if !ok {
+			break
+		}
+		ids = append(ids, article.ID)
+	}
+	// the posts before the failed one are emitted, and the feed is not checkpointed
+	assert.Equal(postIDs(5), ids)
+	assert.NotNil(r.Err())
+	checkpoint, err := NewFileCheckpointStore(path).Load("stub:1")
+	assert.Nil(err)
+	assert.Nil(checkpoint)
+}
@@ -0,0 +1,206 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// values of 'Verified' option
+const (
+	VERIFIED   = "verified"
+	UNVERIFIED = "unverified"
+)
+
+func createFollowingsWeiboService() adapter.ArchiveService {
+	cookieSubLabel := "Cookie SUB"
+	cookieSubDesc := "the 'SUB' part of cookie of the logged-in account"
+	verifiedLabel := "Verified"
+	verifiedDesc := fmt.Sprintf("only archive '%s' or '%s' users", VERIFIED, UNVERIFIED)
+	minFollowersLabel := "Min Followers"
+	minFollowersDesc := "only archive users with at least this count of followers"
+	groupLabel := "Group"
+	groupDesc := "only archive users in this follow group (id)"
+	excludeGroupLabel := "Exclude Group"
+	excludeGroupDesc := "skip users in this follow group (id)"
+	concurrencyLabel := "Concurrency"
+	concurrencyDesc := fmt.Sprintf("count of users archived concurrently, %d by default", DEFAULT_CONCURRENCY)
+	rateLabel := "Requests Per Minute"
+	rateDesc := fmt.Sprintf("request budget shared by all users, %d by default", DEFAULT_REQUESTS_PER_MINUTE)
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "CookieSub",
+			Label:       &cookieSubLabel,
+			Description: &cookieSubDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       1,
+			Name:        "Verified",
+			Label:       &verifiedLabel,
+			Description: &verifiedDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       2,
+			Name:        "MinFollowers",
+			Label:       &minFollowersLabel,
+			Description: &minFollowersDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+		{
+			Order:       3,
+			Name:        "Group",
+			Label:       &groupLabel,
+			Description: &groupDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       4,
+			Name:        "ExcludeGroup",
+			Label:       &excludeGroupLabel,
+			Description: &excludeGroupDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       5,
+			Name:        "Concurrency",
+			Label:       &concurrencyLabel,
+			Description: &concurrencyDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+		{
+			Order:       6,
+			Name:        "RequestsPerMinute",
+			Label:       &rateLabel,
+			Description: &rateDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+	}
+	options = append(options, createRawOptions(7)...)
+	options = append(options, createCheckpointOptions(9)...)
+	options = append(options, createDateRangeOptions(11)...)
+	options = append(options, createContentFilterOptions(13)...)
+	return newWeiboServiceWrapper(
+		"weibo followings",
+		"get all weibo of users followed by the logged-in account",
+		&FollowingsWeiboReader{},
+		options...,
+	)
+}
+
+// FollowingsWeiboReader archive the users followed by the logged-in account
+type FollowingsWeiboReader struct {
+	MultiUserWeiboReader
+	CookieSub    string
+	Verified     string
+	MinFollowers int
+	Group        string
+	ExcludeGroup string
+	retry        retryPolicy
+}
+
+func (r *FollowingsWeiboReader) Init() error {
+	if len(r.CookieSub) == 0 {
+		return errors.New("must provide cookie sub")
+	}
+	switch r.Verified {
+	case "", VERIFIED, UNVERIFIED:
+	default:
+		return fmt.Errorf("invalid verified '%s', should be '%s' or '%s'", r.Verified, VERIFIED, UNVERIFIED)
+	}
+	// followings are enumerated within the same request budget as archiving
+	r.api = r.throttledAPI()
+	r.retry = defaultRetryPolicy
+
+	var uid string
+	if err := r.retry.do(func() (err error) {
+		uid, err = r.api.GetLoginUid(r.CookieSub)
+		return err
+	}); err != nil {
+		return err
+	}
+	followings, err := r.followings(uid)
+	if err != nil {
+		return err
+	}
+	included, err := r.groupMembers(r.Group)
+	if err != nil {
+		return err
+	}
+	excluded, err := r.groupMembers(r.ExcludeGroup)
+	if err != nil {
+		return err
+	}
+
+	var uids []string
+	for _, user := range followings {
+		switch {
+		case r.Verified == VERIFIED && !user.Verified,
+			r.Verified == UNVERIFIED && user.Verified,
+			user.FollowersCount < int64(r.MinFollowers),
+			included != nil && !included[user.Uid()],
+			excluded != nil && excluded[user.Uid()]:
+			continue
+		}
+		uids = append(uids, user.Uid())
+	}
+	log.Printf("archive %d of %d followings", len(uids), len(followings))
+	if len(uids) == 0 {
+		return errors.New("no following matches the options")
+	}
+	return r.start(uids)
+}
+
+// followings of user, all pages
+func (r *FollowingsWeiboReader) followings(uid string) (rt []*api.User, err error) {
+	for page := 1; ; page++ {
+		var users []*api.User
+		if err := r.retry.do(func() (err error) {
+			users, err = r.api.GetFollowings(r.CookieSub, uid, page)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("read page %d of followings failed: %w", page, err)
+		}
+		if len(users) == 0 {
+			return rt, nil
+		}
+		rt = append(rt, users...)
+	}
+}
+
+// groupMembers uid set of follow group, nil if group is not provided
+func (r *FollowingsWeiboReader) groupMembers(gid string) (map[string]bool, error) {
+	if len(gid) == 0 {
+		return nil, nil
+	}
+	rt := map[string]bool{}
+	for page := 1; ; page++ {
+		var members *api.WeiboGroupMembers
+		if err := r.retry.do(func() (err error) {
+			members, err = r.api.GetGroupMembers(r.CookieSub, gid, page)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("read page %d of group '%s' failed: %w", page, gid, err)
+		}
+		users := members.Data.Follows.Users
+		if members.Ok != 1 || len(users) == 0 {
+			return rt, nil
+		}
+		for i := range users {
+			rt[users[i].Uid()] = true
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_HOME_CHECKPOINT = "weibo home timeline:"
+
+func createHomeTimelineWeiboService() adapter.ArchiveService {
+	cookieSubLabel := "Cookie SUB"
+	cookieSubDesc := "the 'SUB' part of cookie of the account"
+	groupLabel := "Group"
+	groupDesc := "the id or title of friend group (分组) to archive only, all followings by default"
+	maxPagesLabel := "Max Pages"
+	maxPagesDesc := "max pages of timeline to read, unlimited by default"
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "CookieSub",
+			Label:       &cookieSubLabel,
+			Description: &cookieSubDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       1,
+			Name:        "Group",
+			Label:       &groupLabel,
+			Description: &groupDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       2,
+			Name:        "MaxPages",
+			Label:       &maxPagesLabel,
+			Description: &maxPagesDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+	}
+	options = append(options, createRawOptions(3)...)
+	options = append(options, createCheckpointOptions(5)...)
+	options = append(options, createDateRangeOptions(7)...)
+	options = append(options, createContentFilterOptions(9)...)
+	return newWeiboServiceWrapper(
+		"weibo home timeline",
+		"get weibo in the home timeline of the logged-in account, or in a friend group",
+		&HomeTimelineWeiboReader{},
+		options...,
+	)
+}
+
+// HomeTimelineWeiboReader of the posts of followings, newest first
+type HomeTimelineWeiboReader struct {
+	feedReader
+	CookieSub string
+	Group     string
+	MaxPages  int
+	gid       string
+	// max id of pages, the timeline is paged by the max id of previous page
+	maxIds map[int]string
+}
+
+func (r *HomeTimelineWeiboReader) Init() error {
+	if len(r.CookieSub) == 0 {
+		return errors.New("must provide the 'SUB' part of cookie")
+	}
+	r.gid = ""
+	r.maxIds = map[int]string{}
+	name := "home timeline"
+	if len(r.Group) > 0 {
+		name = fmt.Sprintf("group '%s'", r.Group)
+	}
+	// the timeline could not be read from a page directly without the max id of previous page
+	r.noCursor = true
+	// the group and account are resolved before the feed, with the api shared by the feed
+	if r.sharedAPI == nil {
+		r.sharedAPI = api.NewWeiboAPI()
+	}
+	if len(r.Group) > 0 {
+		if err := r.resolveGroup(r.sharedAPI); err != nil {
+			return err
+		}
+	}
+	feedKey, err := r.checkpointFeedKey(r.sharedAPI)
+	if err != nil {
+		return err
+	}
+	return r.initFeed(name, feedKey, r.readTimelinePage)
+}
+
+// checkpointFeedKey of the timeline by account and group, the account is known after login,
+// empty if the timeline is not checkpointed
+func (r *HomeTimelineWeiboReader) checkpointFeedKey(weiboAPI *api.WeiboAPI) (string, error) {
+	if !r.Incremental && len(r.CheckpointFile) == 0 {
+		return "", nil
+	}
+	var uid string
+	if err := defaultRetryPolicy.do(func() (err error) {
+		uid, err = weiboAPI.GetLoginUid(r.CookieSub)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return KEY_HOME_CHECKPOINT + uid + ":" + r.gid, nil
+}
+
+// resolveGroup by id or title
+func (r *HomeTimelineWeiboReader) resolveGroup(weiboAPI *api.WeiboAPI) error {
+	var groups []*api.FeedGroup
+	if err := defaultRetryPolicy.do(func() (err error) {
+		groups, err = weiboAPI.GetGroups(r.CookieSub)
+		return err
+	}); err != nil {
+		return fmt.Errorf("read groups failed: %w", err)
+	}
+	for _, group := range groups {
+		if group.Gid.String() == r.Group || group.Title == r.Group {
+			r.gid = group.Gid.String()
+			return nil
+		}
+	}
+	return fmt.Errorf("group '%s' not found", r.Group)
+}
+
+func (r *HomeTimelineWeiboReader) readTimelinePage(page int) ([]*api.Mblog, bool, error) {
+	if r.MaxPages > 0 && page > r.MaxPages {
+		return nil, false, nil
+	}
+	maxId, found := r.maxIds[page]
+	// the previous page is the last one
+	if page > 1 && (!found || maxId == "0") {
+		return nil, false, nil
+	}
+	var timeline *api.WeiboTimeLine
+	var err error
+	if len(r.gid) > 0 {
+		timeline, err = r.api.GetGroupTimeLine(r.CookieSub, r.gid, maxId)
+	} else {
+		timeline, err = r.api.GetTimeLine(r.CookieSub, maxId)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if timeline.Ok != 1 || len(timeline.Data.Mblogs) == 0 {
+		return nil, false, nil
+	}
+	if len(timeline.Data.MaxIDStr) > 0 {
+		r.maxIds[page+1] = timeline.Data.MaxIDStr
+	}
+	return timeline.Data.Mblogs, true, nil
+}
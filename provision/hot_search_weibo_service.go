@@ -0,0 +1,175 @@
+package provision
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_WEIBO_HOT_SEARCH_TYPE = "WeiboHotSearch"
+
+// EXT_HOT_SEARCH_ENTRIES of snapshot, list of rank, keyword, heat and label
+const EXT_HOT_SEARCH_ENTRIES = "hot_search_entries"
+
+// EXT_HOT_SEARCH_KEYWORD of the entry which the post is behind
+const EXT_HOT_SEARCH_KEYWORD = "hot_search_keyword"
+
+func createHotSearchWeiboService() adapter.ArchiveService {
+	topNLabel := "Top N"
+	topNDesc := "fetch the hot posts behind the top N entries, none by default"
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "TopN",
+			Label:       &topNLabel,
+			Description: &topNDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+	}
+	options = append(options, createRawOptions(1)...)
+	return newWeiboServiceWrapper(
+		"weibo hot search",
+		"get a snapshot of hot search board, run it periodically for the history of board",
+		&HotSearchWeiboReader{},
+		options...,
+	)
+}
+
+// HotSearchWeiboReader emit the posts behind the top entries, and finally the snapshot
+// of board referencing them
+type HotSearchWeiboReader struct {
+	RawOptions
+	TopN      int
+	api       *api.WeiboAPI
+	convertor *weiboConvertor
+	retry     retryPolicy
+	err       error
+	tmp       []*model.Article
+	done      bool
+}
+
+func (r *HotSearchWeiboReader) Init() error {
+	r.api = api.NewWeiboAPI()
+	r.convertor = newWeiboConvertor(r.api, newIdSet())
+	r.retry = defaultRetryPolicy
+	r.err = nil
+	r.tmp = nil
+	r.done = false
+	return r.RawOptions.apply(r.convertor)
+}
+
+func (r *HotSearchWeiboReader) Next() (*model.Article, bool) {
+	if !r.done {
+		r.done = true
+		if err := r.readSnapshot(); err != nil {
+			r.err = err
+			return nil, false
+		}
+	}
+	if len(r.tmp) == 0 {
+		return nil, false
+	}
+	rt := r.tmp[0]
+	r.tmp = r.tmp[1:]
+	return rt, len(r.tmp) > 0
+}
+
+// Err stopped reading, nil if the snapshot has been taken
+func (r *HotSearchWeiboReader) Err() error {
+	return r.err
+}
+
+func (r *HotSearchWeiboReader) readSnapshot() error {
+	var board *api.WeiboHotSearch
+	if err := r.retry.do(func() (err error) {
+		board, err = r.api.GetHotSearch()
+		return err
+	}); err != nil {
+		return fmt.Errorf("read hot search failed: %w", err)
+	}
+	entries := board.Entries()
+	snapshot := convertHotSearchSnapshot(entries, time.Now())
+	for _, entry := range entries {
+		if entry.Rank > r.TopN {
+			break
+		}
+		ids, articles := r.readPosts(entry.Keyword)
+		for _, id := range ids {
+			snapshot.References = append(snapshot.References, &model.Reference{
+				Type:        RefTypeHotSearch,
+				ReferenceId: string(id),
+			})
+		}
+		r.tmp = append(r.tmp, articles...)
+	}
+	if err := r.convertor.Err(); err != nil {
+		return fmt.Errorf("convert hot posts failed: %w", err)
+	}
+	r.tmp = append(r.tmp, snapshot)
+	return nil
+}
+
+// readPosts of the first page of hot search results, return the ids of posts and the unseen articles.
+// the failure is not fatal to the snapshot
+func (r *HotSearchWeiboReader) readPosts(keyword string) (ids []model.ID, rt []*model.Article) {
+	var page *api.WeiboUserListPageIndex
+	if err := r.retry.do(func() (err error) {
+		page, err = r.api.SearchHot(keyword, 1)
+		return err
+	}); err != nil {
+		log.Printf("read hot posts of '%s' failed: %v", keyword, err)
+		return nil, nil
+	}
+	for _, mblog := range extractMblogs(page.Data.Cards) {
+		id := model.CreateID(KEY_WEIBO_ARTICLE_TYPE, stringOf(mblog.ID))
+		ids = append(ids, id)
+		for _, article := range r.convertor.convertMblog(mblog) {
+			if article.ID == id {
+				article.ExtAttributes[EXT_HOT_SEARCH_KEYWORD] = keyword
+			}
+			rt = append(rt, article)
+		}
+	}
+	return ids, rt
+}
+
+func convertHotSearchSnapshot(entries []*api.HotSearchEntry, snapshotAt time.Time) *model.Article {
+	snapshotAt = snapshotAt.In(weiboLocation).Truncate(time.Second)
+	title := fmt.Sprintf("微博热搜 (%s)", snapshotAt.Format("2006-01-02 15:04:05"))
+	lines := []string{
+		fmt.Sprintf("# %s", title),
+		"",
+		"| 排名 | 关键词 | 热度 | 标签 |",
+		"| --- | --- | --- | --- |",
+	}
+	records := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("| %d | %s | %d | %s |", entry.Rank, entry.Keyword, entry.Heat, entry.Label))
+		records = append(records, map[string]interface{}{
+			"rank":    entry.Rank,
+			"keyword": entry.Keyword,
+			"heat":    entry.Heat,
+			"label":   entry.Label,
+		})
+	}
+	content := strings.Join(lines, "\n")
+	return &model.Article{
+		ID:          model.CreateID(KEY_WEIBO_HOT_SEARCH_TYPE, snapshotAt.Unix()),
+		Type:        KEY_WEIBO_HOT_SEARCH_TYPE,
+		Title:       &title,
+		PublishDate: &snapshotAt,
+		Content:     &content,
+		ExtAttributes: map[string]interface{}{
+			EXT_SNAPSHOT_AT:        snapshotAt.Format(time.RFC3339),
+			EXT_HOT_SEARCH_ENTRIES: records,
+			EXT_ACCESS_LEVEL:       ACCESS_PUBLIC,
+		},
+	}
+}
@@ -0,0 +1,258 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const DEFAULT_CONCURRENCY = 3
+const DEFAULT_REQUESTS_PER_MINUTE = 60
+
+func createMultiUserWeiboService() adapter.ArchiveService {
+	uidsLabel := "Weibo User IDs"
+	uidsDesc := "the 'uid' list of weibo users, separated by comma or space"
+	uidFileLabel := "Weibo User ID File"
+	uidFileDesc := "file of 'uid' list, one per line, lines start with '#' are ignored"
+	concurrencyLabel := "Concurrency"
+	concurrencyDesc := fmt.Sprintf("count of users archived concurrently, %d by default", DEFAULT_CONCURRENCY)
+	rateLabel := "Requests Per Minute"
+	rateDesc := fmt.Sprintf("request budget shared by all users, %d by default", DEFAULT_REQUESTS_PER_MINUTE)
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "Uids",
+			Label:       &uidsLabel,
+			Description: &uidsDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       1,
+			Name:        "UidFile",
+			Label:       &uidFileLabel,
+			Description: &uidFileDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       2,
+			Name:        "Concurrency",
+			Label:       &concurrencyLabel,
+			Description: &concurrencyDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+		{
+			Order:       3,
+			Name:        "RequestsPerMinute",
+			Label:       &rateLabel,
+			Description: &rateDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+	}
+	options = append(options, createRawOptions(4)...)
+	options = append(options, createCheckpointOptions(6)...)
+	options = append(options, createDateRangeOptions(8)...)
+	options = append(options, createContentFilterOptions(10)...)
+	return newWeiboServiceWrapper(
+		"weibo users",
+		"get all weibo of multiple users concurrently",
+		&MultiUserWeiboReader{},
+		options...,
+	)
+}
+
+// MultiUserError of the users failed to archive, the other users are not affected
+type MultiUserError struct {
+	Errors map[string]error
+}
+
+func (e *MultiUserError) Error() string {
+	uids := make([]string, 0, len(e.Errors))
+	for uid := range e.Errors {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	messages := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		messages = append(messages, fmt.Sprintf("'%s': %v", uid, e.Errors[uid]))
+	}
+	return fmt.Sprintf("archive failed for %d users, %s", len(uids), strings.Join(messages, "; "))
+}
+
+type MultiUserWeiboReader struct {
+	RawOptions
+	CheckpointOptions
+	DateRangeOptions
+	ContentFilterOptions
+	Uids              string
+	UidFile           string
+	Concurrency       int
+	RequestsPerMinute int
+	api               *api.WeiboAPI
+	articles          chan *model.Article
+	mu                sync.Mutex
+	errs              map[string]error
+}
+
+func (r *MultiUserWeiboReader) Init() error {
+	uids, err := r.uids()
+	if err != nil {
+		return err
+	}
+	if len(uids) == 0 {
+		return errors.New("must provide uids or uid file")
+	}
+	r.api = r.throttledAPI()
+	return r.start(uids)
+}
+
+// throttledAPI within the request budget, shared by all requests of the reader
+func (r *MultiUserWeiboReader) throttledAPI() *api.WeiboAPI {
+	rate := r.RequestsPerMinute
+	if rate <= 0 {
+		rate = DEFAULT_REQUESTS_PER_MINUTE
+	}
+	weiboAPI := api.NewWeiboAPI()
+	weiboAPI.SetRequestInterval(time.Minute / time.Duration(rate))
+	return weiboAPI
+}
+
+// start archiving users in goroutines with the throttled api
+func (r *MultiUserWeiboReader) start(uids []string) error {
+	// fail fast on invalid options, instead of failing for each user
+	if _, err := r.DateRangeOptions.dateRange(); err != nil {
+		return err
+	}
+	if _, err := r.ContentFilterOptions.contentFilter(); err != nil {
+		return err
+	}
+	concurrency := r.Concurrency
+	if concurrency <= 0 {
+		concurrency = DEFAULT_CONCURRENCY
+	}
+	weiboAPI := r.api
+	emitted := newIdSet()
+
+	r.articles = make(chan *model.Article, 100)
+	r.errs = map[string]error{}
+
+	go func() {
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, concurrency)
+		for i, uid := range uids {
+			slots <- struct{}{}
+			wg.Add(1)
+			go func(i int, uid string) {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+				r.archiveUser(r.userReader(weiboAPI, emitted, uid), uid, fmt.Sprintf("%d/%d", i+1, len(uids)))
+			}(i, uid)
+		}
+		wg.Wait()
+		close(r.articles)
+	}()
+	return nil
+}
+
+// userReader of uid sharing the api and emitted articles with other users
+func (r *MultiUserWeiboReader) userReader(weiboAPI *api.WeiboAPI, emitted *idSet, uid string) FallibleArticleReader {
+	return &SingleUserWeiboReader{
+		feedReader: feedReader{
+			RawOptions:           r.RawOptions,
+			CheckpointOptions:    r.CheckpointOptions,
+			DateRangeOptions:     r.DateRangeOptions,
+			ContentFilterOptions: r.ContentFilterOptions,
+			sharedAPI:            weiboAPI,
+			sharedEmitted:        emitted,
+		},
+		Uid: uid,
+	}
+}
+
+// archiveUser with its reader, failure is isolated to the user
+func (r *MultiUserWeiboReader) archiveUser(reader FallibleArticleReader, uid string, progress string) {
+	log.Printf("[%s] start archiving user '%s'", progress, uid)
+	if err := reader.Init(); err != nil {
+		r.fail(uid, err)
+		return
+	}
+	count := 0
+	for {
+		article, next := reader.Next()
+		if article != nil {
+			r.articles <- article
+			count++
+		}
+		if !next {
+			break
+		}
+	}
+	if err := reader.Err(); err != nil {
+		log.Printf("[%s] archived %d articles of user '%s', failed: %v", progress, count, uid, err)
+		r.fail(uid, err)
+		return
+	}
+	log.Printf("[%s] archived %d articles of user '%s'", progress, count, uid)
+}
+
+func (r *MultiUserWeiboReader) fail(uid string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs[uid] = err
+}
+
+// uids from option and file, duplicates are removed
+func (r *MultiUserWeiboReader) uids() (rt []string, err error) {
+	values := strings.FieldsFunc(r.Uids, func(c rune) bool {
+		return c == ',' || c == ' ' || c == '\n' || c == '\t'
+	})
+	if len(r.UidFile) > 0 {
+		data, err := os.ReadFile(r.UidFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); len(line) > 0 && !strings.HasPrefix(line, "#") {
+				values = append(values, line)
+			}
+		}
+	}
+	seen := map[string]bool{}
+	for _, uid := range values {
+		if !seen[uid] {
+			seen[uid] = true
+			rt = append(rt, uid)
+		}
+	}
+	return rt, nil
+}
+
+func (r *MultiUserWeiboReader) Next() (*model.Article, bool) {
+	article, ok := <-r.articles
+	return article, ok
+}
+
+// Err of failed users after all users have been read
+func (r *MultiUserWeiboReader) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.errs) == 0 {
+		return nil
+	}
+	return &MultiUserError{r.errs}
+}
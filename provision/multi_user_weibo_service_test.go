@@ -0,0 +1,78 @@
+package provision
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiUserWeiboReader_FailureIsolated(t *testing.T) {
+	assert := assert.New(t)
+
+	r := &MultiUserWeiboReader{articles: make(chan *model.Article, 10), errs: map[string]error{}}
+	notFound := &api.StatusError{Endpoint: "stub", StatusCode: 404}
+	r.archiveUser(&stubFeedReader{pages: [][]int{{2, 1}}}, "1", "1/4")
+	// failed after the first page
+	r.archiveUser(&stubFeedReader{pages: [][]int{{4, 3}}, errors: []error{nil, notFound}}, "2", "2/4")
+	// failed to init
+	invalid := &stubFeedReader{pages: [][]int{{5}}}
+	invalid.Since = "yesterday"
+	r.archiveUser(invalid, "3", "3/4")
+	r.archiveUser(&stubFeedReader{pages: [][]int{{6}}}, "4", "4/4")
+	close(r.articles)
+
+	var ids []model.ID
+	for {
+		article, ok := r.Next()
+		if !ok {
+			break
+		}
+		ids = append(ids, article.ID)
+	}
+	// the articles read before failure are kept
+	assert.Equal(postIDs(2, 1, 4, 3, 6), ids)
+
+	var multiErr *MultiUserError
+	assert.True(errors.As(r.Err(), &multiErr))
+	assert.Len(multiErr.Errors, 2)
+	assert.True(errors.Is(multiErr.Errors["2"], notFound))
+	assert.NotNil(multiErr.Errors["3"])
+	assert.Contains(multiErr.Error(), "archive failed for 2 users, '2': ")
+	assert.Contains(multiErr.Error(), "; '3': ")
+}
+
+func TestMultiUserWeiboReader_NoError(t *testing.T) {
+	r := &MultiUserWeiboReader{articles: make(chan *model.Article, 10), errs: map[string]error{}}
+	r.archiveUser(&stubFeedReader{pages: [][]int{{1}}}, "1", "1/1")
+	assert.Nil(t, r.Err())
+}
+
+func TestMultiUserWeiboReader_Uids(t *testing.T) {
+	assert := assert.New(t)
+
+	file := filepath.Join(t.TempDir(), "uids.txt")
+	if err := os.WriteFile(file, []byte("# friends\n 3 \n\n2\n#4\n5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := &MultiUserWeiboReader{Uids: "1, 2\t3 ,", UidFile: file}
+	uids, err := r.uids()
+	assert.Nil(err)
+	assert.Equal([]string{"1", "2", "3", "5"}, uids)
+
+	r = &MultiUserWeiboReader{Uids: "1 1"}
+	uids, err = r.uids()
+	assert.Nil(err)
+	assert.Equal([]string{"1"}, uids)
+
+	r = &MultiUserWeiboReader{UidFile: filepath.Join(t.TempDir(), "missing.txt")}
+	_, err = r.uids()
+	assert.NotNil(err)
+
+	r = &MultiUserWeiboReader{}
+	assert.NotNil(r.Init())
+}
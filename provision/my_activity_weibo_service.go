@@ -0,0 +1,200 @@
+package provision
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_WEIBO_COMMENT_TYPE = "WeiboComment"
+const KEY_WEIBO_LIKE_TYPE = "WeiboLike"
+const KEY_WEIBO_MENTION_TYPE = "WeiboMention"
+
+// EXT_REPLY_COMMENT_ID id of the comment replied by comment, string
+const EXT_REPLY_COMMENT_ID = "reply_comment_id"
+
+// activities of the logged-in account
+const (
+	ACTIVITY_COMMENTS_BY_ME = "comments by me"
+	ACTIVITY_COMMENTS_TO_ME = "comments to me"
+	ACTIVITY_MENTIONS       = "mentions"
+	ACTIVITY_LIKES          = "likes"
+)
+
+func createMyActivityWeiboServices() []adapter.ArchiveService {
+	descriptions := map[string]string{
+		ACTIVITY_COMMENTS_BY_ME: "get all comments written by the logged-in account, with the commented posts",
+		ACTIVITY_COMMENTS_TO_ME: "get all comments received by the logged-in account, with the commented posts",
+		ACTIVITY_MENTIONS:       "get all posts mentioning the logged-in account",
+		ACTIVITY_LIKES:          "get all posts liked by the logged-in account",
+	}
+	rt := []adapter.ArchiveService{}
+	for _, kind := range []string{ACTIVITY_COMMENTS_BY_ME, ACTIVITY_COMMENTS_TO_ME, ACTIVITY_MENTIONS, ACTIVITY_LIKES} {
+		rt = append(rt, newWeiboServiceWrapper(
+			"weibo "+kind,
+			descriptions[kind],
+			&MyActivityWeiboReader{kind: kind},
+			createActivityOptions(0)...,
+		))
+	}
+	return rt
+}
+
+// MyActivityWeiboReader emit each activity as an article referencing the target post,
+// after the post itself
+type MyActivityWeiboReader struct {
+	activityReader
+	kind string
+}
+
+func (r *MyActivityWeiboReader) Init() error {
+	readPage := r.readLikes
+	switch r.kind {
+	case ACTIVITY_COMMENTS_BY_ME:
+		readPage = r.readComments((*api.WeiboAPI).GetCommentsByMe)
+	case ACTIVITY_COMMENTS_TO_ME:
+		readPage = r.readComments((*api.WeiboAPI).GetCommentsToMe)
+	case ACTIVITY_MENTIONS:
+		readPage = r.readMentions
+	}
+	// likes are identified by the post ids, the run stops at the recent archived likes
+	r.unorderedIds = r.kind == ACTIVITY_LIKES
+	return r.initActivities(r.kind, "weibo "+r.kind+":", readPage)
+}
+
+func (r *MyActivityWeiboReader) readComments(getComments func(weiboAPI *api.WeiboAPI, cookieSub string, page int) (*api.WeiboComments, error)) activityPageReader {
+	return func(page int) (rt []activity, err error) {
+		comments, err := getComments(r.api, r.CookieSub, page)
+		if err != nil || comments.Ok != 1 {
+			return nil, err
+		}
+		for _, comment := range comments.Data {
+			comment := comment
+			var createdAt *time.Time
+			if t, err := time.Parse(time.RubyDate, comment.CreatedAt); err == nil {
+				createdAt = &t
+			}
+			rt = append(rt, activity{
+				id:      comment.ID.String(),
+				date:    createdAt,
+				convert: func() []*model.Article { return r.convertComment(comment) },
+			})
+		}
+		return rt, nil
+	}
+}
+
+func (r *MyActivityWeiboReader) readMentions(page int) (rt []activity, err error) {
+	mentions, err := r.api.GetMentions(r.CookieSub, page)
+	if err != nil || mentions.Ok != 1 {
+		return nil, err
+	}
+	return r.postActivities(mentions.Data, mblogPublishDate, r.convertMention), nil
+}
+
+func (r *MyActivityWeiboReader) readLikes(page int) (rt []activity, err error) {
+	likes, err := r.api.GetLikes(r.CookieSub, r.uid, page)
+	if err != nil || likes.Ok != 1 {
+		return nil, err
+	}
+	// the time of like is unknown, likes are not limited by date range
+	return r.postActivities(likes.Data.List, nil, r.convertLike), nil
+}
+
+// postActivities of posts, dated by the optional dateOf
+func (r *MyActivityWeiboReader) postActivities(mblogs []*api.Mblog, dateOf func(*api.Mblog) *time.Time, convert func(*api.Mblog) *model.Article) (rt []activity) {
+	for _, mblog := range mblogs {
+		mblog := mblog
+		var date *time.Time
+		if dateOf != nil {
+			date = dateOf(mblog)
+		}
+		rt = append(rt, activity{
+			id:   stringOf(mblog.ID),
+			date: date,
+			convert: func() []*model.Article {
+				return append(r.convertor.convertMblog(mblog), convert(mblog))
+			},
+		})
+	}
+	return rt
+}
+
+// convertComment with the commented post, the replied comment is quoted in content
+func (r *MyActivityWeiboReader) convertComment(comment *api.Comment) (rt []*model.Article) {
+	// the comment is visible to the audience of the commented post
+	ext := extAttributes{EXT_ACCESS_LEVEL: ACCESS_RESTRICTED}
+	if comment.Status != nil {
+		ext[EXT_ACCESS_LEVEL] = mblogAccessLevel(comment.Status)
+	}
+	ext.setString(EXT_SOURCE, &comment.Source)
+	ext.setInt(EXT_ATTITUDES_COUNT, comment.LikeCount)
+	article := &model.Article{
+		ID:            model.CreateID(KEY_WEIBO_COMMENT_TYPE, comment.ID.String()),
+		Type:          KEY_WEIBO_COMMENT_TYPE,
+		Content:       r.convertor.markdown(comment.Text),
+		Medias:        []*model.Media{},
+		ExtAttributes: ext,
+	}
+	if createdAt, err := time.Parse(time.RubyDate, comment.CreatedAt); err == nil {
+		article.PublishDate = &createdAt
+	}
+	if comment.User != nil {
+		article.Author = r.convertor.convertUser(comment.User)
+	}
+	if reply := comment.ReplyComment; reply != nil {
+		ext[EXT_REPLY_COMMENT_ID] = reply.ID.String()
+		quote := *r.convertor.markdown(reply.Text)
+		if reply.User != nil {
+			quote = fmt.Sprintf("@%s: %s", reply.User.ScreenName, quote)
+		}
+		content := fmt.Sprintf("%s\n\n> %s", *article.Content, quote)
+		article.Content = &content
+	}
+	if comment.Status != nil && comment.Status.ID != nil {
+		rt = append(rt, r.convertor.convertMblog(comment.Status)...)
+		article.References = append(article.References, &model.Reference{
+			Type:        RefTypeComment,
+			ReferenceId: string(model.CreateID(KEY_WEIBO_ARTICLE_TYPE, *comment.Status.ID)),
+		})
+	}
+	if r.convertor.unseen(article) {
+		rt = append(rt, article)
+	}
+	return rt
+}
+
+// convertMention of the account by post, authored by the author of post
+func (r *MyActivityWeiboReader) convertMention(mblog *api.Mblog) *model.Article {
+	article := &model.Article{
+		ID:            model.CreateID(KEY_WEIBO_MENTION_TYPE, stringOf(mblog.ID)),
+		Type:          KEY_WEIBO_MENTION_TYPE,
+		PublishDate:   mblogPublishDate(mblog),
+		ExtAttributes: map[string]interface{}{EXT_ACCESS_LEVEL: mblogAccessLevel(mblog)},
+		References: []*model.Reference{{
+			Type:        RefTypeMention,
+			ReferenceId: string(model.CreateID(KEY_WEIBO_ARTICLE_TYPE, stringOf(mblog.ID))),
+		}},
+	}
+	if mblog.User != nil {
+		article.Author = r.convertor.convertUser(mblog.User)
+	}
+	return article
+}
+
+// convertLike of post by the account, weibo does not provide the time of like
+func (r *MyActivityWeiboReader) convertLike(mblog *api.Mblog) *model.Article {
+	return &model.Article{
+		ID:            model.CreateID(KEY_WEIBO_LIKE_TYPE, r.uid+":"+stringOf(mblog.ID)),
+		Type:          KEY_WEIBO_LIKE_TYPE,
+		Author:        &model.Author{ID: model.CreateID(KEY_WEIBO_USER_TYPE, r.uid)},
+		ExtAttributes: map[string]interface{}{EXT_ACCESS_LEVEL: mblogAccessLevel(mblog)},
+		References: []*model.Reference{{
+			Type:        RefTypeLike,
+			ReferenceId: string(model.CreateID(KEY_WEIBO_ARTICLE_TYPE, stringOf(mblog.ID))),
+		}},
+	}
+}
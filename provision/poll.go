@@ -0,0 +1,76 @@
+package provision
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// applyPoll of post to the article with the fetched results
+func (c *weiboConvertor) applyPoll(article *model.Article, ext extAttributes, pageInfo *api.PageInfo) {
+	pollId := api.GetPollId(pageInfo)
+	if len(pollId) == 0 {
+		return
+	}
+	var poll *api.WeiboPoll
+	if err := c.retry.do(func() (err error) {
+		poll, err = c.api.GetPoll(pollId)
+		return err
+	}); err != nil {
+		c.fail(fmt.Errorf("fetch poll '%s' failed: %w", pollId, err))
+		return
+	}
+	info := poll.Data.VoteInfo
+	info.VoteID = pollId
+	setPoll(article, ext, &info)
+}
+
+// setPoll rendered into the content of article, and the results into ext attributes
+func setPoll(article *model.Article, ext extAttributes, info *api.PollInfo) {
+	ext[EXT_POLL_ID] = info.VoteID
+	ext.setString(EXT_POLL_QUESTION, &info.Content)
+	if len(info.OptionList) > 0 {
+		options := make([]map[string]interface{}, 0, len(info.OptionList))
+		for _, option := range info.OptionList {
+			options = append(options, map[string]interface{}{
+				"option": option.Content,
+				"votes":  option.PartNum,
+			})
+		}
+		ext[EXT_POLL_PARTICIPANTS] = info.PartNum
+		ext[EXT_POLL_OPTIONS] = options
+		ext[EXT_POLL_FINAL] = info.Expired
+	}
+	content := renderPoll(info)
+	if article.Content != nil && len(*article.Content) > 0 {
+		content = *article.Content + "\n\n" + content
+	}
+	article.Content = &content
+}
+
+// renderPoll as markdown, e.g.
+//
+//	**投票: 午饭吃什么** (30人参与)
+//	- 米饭: 20 (66.7%)
+//	- 面条: 10 (33.3%)
+func renderPoll(info *api.PollInfo) string {
+	title := fmt.Sprintf("**投票: %s**", info.Content)
+	if len(info.OptionList) == 0 {
+		return title
+	}
+	lines := []string{fmt.Sprintf("%s (%d人参与)", title, info.PartNum)}
+	var total int64
+	for _, option := range info.OptionList {
+		total += option.PartNum
+	}
+	for _, option := range info.OptionList {
+		ratio := 0.0
+		if total > 0 {
+			ratio = float64(option.PartNum) * 100 / float64(total)
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %d (%.1f%%)", option.Content, option.PartNum, ratio))
+	}
+	return strings.Join(lines, "\n")
+}
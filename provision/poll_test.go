@@ -0,0 +1,61 @@
+package provision
+
+import (
+	"testing"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPoll(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("**投票: 午饭吃什么** (30人参与)\n- 米饭: 20 (66.7%)\n- 面条: 10 (33.3%)", renderPoll(&api.PollInfo{
+		Content: "午饭吃什么",
+		PartNum: 30,
+		OptionList: []api.PollOption{
+			{Content: "米饭", PartNum: 20},
+			{Content: "面条", PartNum: 10},
+		},
+	}))
+	// no votes yet
+	assert.Equal("**投票: 午饭吃什么** (0人参与)\n- 米饭: 0 (0.0%)\n- 面条: 0 (0.0%)", renderPoll(&api.PollInfo{
+		Content:    "午饭吃什么",
+		OptionList: []api.PollOption{{Content: "米饭"}, {Content: "面条"}},
+	}))
+	// the results are not fetched
+	assert.Equal("**投票: 午饭吃什么**", renderPoll(&api.PollInfo{Content: "午饭吃什么"}))
+}
+
+func TestSetPoll(t *testing.T) {
+	assert := assert.New(t)
+
+	text := "大家来投票"
+	article := &model.Article{Content: &text}
+	ext := extAttributes{}
+	setPoll(article, ext, &api.PollInfo{
+		VoteID:     "123",
+		Content:    "午饭吃什么",
+		Expired:    true,
+		OptionList: []api.PollOption{{Content: "米饭"}, {Content: "面条"}},
+	})
+	assert.Equal("大家来投票\n\n**投票: 午饭吃什么** (0人参与)\n- 米饭: 0 (0.0%)\n- 面条: 0 (0.0%)", *article.Content)
+	assert.Equal("123", ext[EXT_POLL_ID])
+	assert.Equal("午饭吃什么", ext[EXT_POLL_QUESTION])
+	assert.Equal(int64(0), ext[EXT_POLL_PARTICIPANTS])
+	assert.Equal(true, ext[EXT_POLL_FINAL])
+	assert.Equal([]map[string]interface{}{
+		{"option": "米饭", "votes": int64(0)},
+		{"option": "面条", "votes": int64(0)},
+	}, ext[EXT_POLL_OPTIONS])
+
+	// only the question is known if the results are not fetched
+	article = &model.Article{}
+	ext = extAttributes{}
+	setPoll(article, ext, &api.PollInfo{VoteID: "123", Content: "午饭吃什么"})
+	assert.Equal("**投票: 午饭吃什么**", *article.Content)
+	assert.Equal("123", ext[EXT_POLL_ID])
+	assert.NotContains(ext, EXT_POLL_OPTIONS)
+	assert.NotContains(ext, EXT_POLL_FINAL)
+}
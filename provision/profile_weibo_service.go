@@ -0,0 +1,144 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_WEIBO_PROFILE_TYPE = "WeiboProfile"
+
+// EXT_SNAPSHOT_AT time of profile snapshot, string in RFC3339
+const EXT_SNAPSHOT_AT = "snapshot_at"
+
+func createProfileWeiboService() adapter.ArchiveService {
+	uidLabel := "Weibo User ID"
+	uidDesc := "the 'uid' of weibo user"
+	return newWeiboServiceWrapper(
+		"weibo user profile",
+		"get a snapshot of the profile of single user, including avatar and cover",
+		&ProfileWeiboReader{},
+		&adapter.Option{
+			Order:       0,
+			Name:        "Uid",
+			Label:       &uidLabel,
+			Description: &uidDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+	)
+}
+
+// ProfileWeiboReader emit a timestamped snapshot of user profile for each run,
+// so that the changes of profile could be traced over years
+type ProfileWeiboReader struct {
+	Uid   string
+	api   *api.WeiboAPI
+	retry retryPolicy
+	err   error
+	done  bool
+}
+
+func (r *ProfileWeiboReader) Init() error {
+	if len(r.Uid) == 0 {
+		return errors.New("must provide uid")
+	}
+	r.api = api.NewWeiboAPI()
+	r.retry = defaultRetryPolicy
+	r.err = nil
+	r.done = false
+	return nil
+}
+
+func (r *ProfileWeiboReader) Next() (*model.Article, bool) {
+	if r.done {
+		return nil, false
+	}
+	r.done = true
+	var info *api.DataUserInfo
+	if err := r.retry.do(func() (err error) {
+		info, err = r.api.GetUserProfile(r.Uid)
+		return err
+	}); err != nil {
+		r.err = fmt.Errorf("read profile of user '%s' failed: %w", r.Uid, err)
+		return nil, false
+	}
+	snapshot := convertProfileSnapshot(info, time.Now())
+	var detail *api.UserDetail
+	if err := r.retry.do(func() (err error) {
+		detail, err = r.api.GetUserDetail(r.Uid)
+		return err
+	}); err != nil {
+		// the extended profile may be hidden by user
+		log.Printf("read extended profile of user '%s' failed: %v", r.Uid, err)
+	} else {
+		applyUserDetail(snapshot.Author, detail, time.Now())
+		for _, key := range []string{EXT_USER_BIRTHDAY, EXT_USER_EDUCATION, EXT_USER_COMPANY, EXT_USER_REGISTERED_AT, EXT_USER_CREDIT_LEVEL} {
+			if value, found := snapshot.Author.ExtAttributes[key]; found {
+				snapshot.ExtAttributes[key] = value
+			}
+		}
+	}
+	return snapshot, false
+}
+
+// Err stopped reading, nil if the snapshot has been taken
+func (r *ProfileWeiboReader) Err() error {
+	return r.err
+}
+
+func convertProfileSnapshot(info *api.DataUserInfo, snapshotAt time.Time) *model.Article {
+	snapshotAt = snapshotAt.In(weiboLocation).Truncate(time.Second)
+	author := convertUserInfo(info)
+	title := fmt.Sprintf("%s 的资料 (%s)", info.ScreenName, snapshotAt.Format("2006-01-02 15:04:05"))
+
+	lines := []string{
+		fmt.Sprintf("# %s", info.ScreenName),
+		"",
+		info.Description,
+		"",
+	}
+	if info.Verified {
+		lines = append(lines, fmt.Sprintf("- 认证: %s", info.VerifiedReason))
+	}
+	lines = append(lines,
+		fmt.Sprintf("- 微博: %d", info.StatusesCount),
+		fmt.Sprintf("- 关注: %d", info.FollowCount),
+		fmt.Sprintf("- 粉丝: %d", info.FollowersCount),
+	)
+	content := strings.Join(lines, "\n")
+
+	ext := userInfoExtAttributes(info)
+	ext[EXT_SNAPSHOT_AT] = snapshotAt.Format(time.RFC3339)
+	ext[EXT_ACCESS_LEVEL] = ACCESS_PUBLIC
+	article := &model.Article{
+		ID:            model.CreateID(KEY_WEIBO_PROFILE_TYPE, fmt.Sprintf("%d@%d", info.ID, snapshotAt.Unix())),
+		Type:          KEY_WEIBO_PROFILE_TYPE,
+		Title:         &title,
+		Author:        author,
+		PublishDate:   &snapshotAt,
+		Content:       &content,
+		Medias:        []*model.Media{},
+		ExtAttributes: ext,
+	}
+	imageType := "image/jpg"
+	for _, image := range []string{info.AvatarHD, info.CoverImagePhone} {
+		if len(image) == 0 {
+			continue
+		}
+		link := image
+		article.Medias = append(article.Medias, &model.Media{
+			ID:           model.CreateID(KEY_WEIBO_RESOURCE_TYPE, link),
+			MimeType:     &imageType,
+			ExternalLink: &link,
+		})
+	}
+	return article
+}
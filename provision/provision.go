@@ -8,7 +8,20 @@ type WeiboServiceProvision struct {
 }
 
 func (p WeiboServiceProvision) ProvideServices() []adapter.ArchiveService {
-	return []adapter.ArchiveService{
+	rt := []adapter.ArchiveService{
 		createSingleUserWeiboService(),
+		createMultiUserWeiboService(),
+		createFollowingsWeiboService(),
+		createSocialGraphWeiboService(),
+		createProfileWeiboService(),
+		createSearchWeiboService(),
+		createSearchUserWeiboService(),
+		createTopicWeiboService(),
+		createHotSearchWeiboService(),
+		createFavoritesWeiboService(),
+		createHomeTimelineWeiboService(),
+		createAlbumWeiboService(),
+		createVideoWeiboService(),
 	}
+	return append(rt, createMyActivityWeiboServices()...)
 }
@@ -0,0 +1,75 @@
+package provision
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+)
+
+// RawStore keep the raw json of weibo entities as returned by weibo, keyed by archive id,
+// so that data could be re-derived later when the schema evolves
+type RawStore interface {
+	Put(id model.ID, raw []byte) error
+}
+
+// NewDirRawStore store each raw json as '<id>.json' file under the directory
+func NewDirRawStore(dir string) (RawStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &dirRawStore{dir}, nil
+}
+
+type dirRawStore struct {
+	dir string
+}
+
+func (s *dirRawStore) Put(id model.ID, raw []byte) error {
+	return os.WriteFile(filepath.Join(s.dir, string(id)+".json"), raw, 0644)
+}
+
+// RawOptions of weibo services, embedded in readers to keep raw json
+type RawOptions struct {
+	KeepRaw bool
+	RawDir  string
+}
+
+func createRawOptions(order int) []*adapter.Option {
+	keepRawLabel := "Keep Raw JSON"
+	keepRawDesc := "attach the raw json of posts and users returned by weibo to the archived articles"
+	rawDirLabel := "Raw JSON Directory"
+	rawDirDesc := "write the raw json of posts and users to this directory, named by archive id"
+	return []*adapter.Option{
+		{
+			Order:       order,
+			Name:        "KeepRaw",
+			Label:       &keepRawLabel,
+			Description: &keepRawDesc,
+			Optional:    true,
+			ValueType:   reflect.Bool,
+		},
+		{
+			Order:       order + 1,
+			Name:        "RawDir",
+			Label:       &rawDirLabel,
+			Description: &rawDirDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+	}
+}
+
+func (o *RawOptions) apply(c *weiboConvertor) error {
+	c.attachRaw = o.KeepRaw
+	if len(o.RawDir) > 0 {
+		store, err := NewDirRawStore(o.RawDir)
+		if err != nil {
+			return err
+		}
+		c.rawStore = store
+	}
+	return nil
+}
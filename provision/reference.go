@@ -0,0 +1,23 @@
+package provision
+
+import "github.com/ArchiveLife/core/model"
+
+// reference types of weibo, start from 100 to avoid conflict with the types in core
+const (
+	// RefTypeHeadlineArticle link a teaser post to its headline article (头条文章)
+	RefTypeHeadlineArticle model.ReferenceType = iota + 100
+	// RefTypeRetweet link a repost to the original post
+	RefTypeRetweet
+	// RefTypeFollowing link an user to the user followed by
+	RefTypeFollowing
+	// RefTypeFollower link an user to the follower
+	RefTypeFollower
+	// RefTypeComment link a comment to the commented post
+	RefTypeComment
+	// RefTypeHotSearch link a hot search snapshot to the post behind an entry
+	RefTypeHotSearch
+	// RefTypeLike link a like record to the liked post
+	RefTypeLike
+	// RefTypeMention link a mention record to the post mentioning the account
+	RefTypeMention
+)
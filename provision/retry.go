@@ -0,0 +1,29 @@
+package provision
+
+import (
+	"log"
+	"time"
+
+	"github.com/ArchiveLife/weibo/api"
+)
+
+// retryPolicy of weibo api calls, only transient failures are retried
+type retryPolicy struct {
+	attempts int
+	// backoff of first retry, doubled for each following retry
+	backoff time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{attempts: 5, backoff: 3 * time.Second}
+
+func (p retryPolicy) do(fn func() error) (err error) {
+	backoff := p.backoff
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || !api.IsTransient(err) || attempt >= p.attempts {
+			return err
+		}
+		log.Printf("weibo api failed (attempt %d/%d), retry after %s: %v", attempt, p.attempts, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,165 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_SEARCH_CHECKPOINT = "weibo search:"
+
+func createSearchWeiboService() adapter.ArchiveService {
+	keywordLabel := "Keyword"
+	keywordDesc := "the query to search, e.g. a word, '#topic#' or a phrase"
+	hotLabel := "Hot"
+	hotDesc := "search hot posts instead of real-time ones, the hot posts are not ordered by time so could not be read incrementally"
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "Keyword",
+			Label:       &keywordLabel,
+			Description: &keywordDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       1,
+			Name:        "Hot",
+			Label:       &hotLabel,
+			Description: &hotDesc,
+			Optional:    true,
+			ValueType:   reflect.Bool,
+		},
+	}
+	options = append(options, createRawOptions(2)...)
+	options = append(options, createCheckpointOptions(4)...)
+	options = append(options, createDateRangeOptions(6)...)
+	options = append(options, createContentFilterOptions(8)...)
+	return newWeiboServiceWrapper(
+		"weibo search",
+		"get weibo matching the keyword, run it incrementally to track the discussion over time",
+		&SearchWeiboReader{},
+		options...,
+	)
+}
+
+// SearchWeiboReader of search results, real-time results are newest first so
+// that the incremental runs stop at the archived posts
+type SearchWeiboReader struct {
+	feedReader
+	Keyword string
+	Hot     bool
+}
+
+func (r *SearchWeiboReader) Init() error {
+	if len(r.Keyword) == 0 {
+		return errors.New("must provide keyword")
+	}
+	searchType := api.SEARCH_TYPE_REALTIME
+	if r.Hot {
+		searchType = api.SEARCH_TYPE_HOT
+	}
+	r.unordered = r.Hot
+	return r.initFeed(
+		fmt.Sprintf("search '%s'", r.Keyword),
+		KEY_SEARCH_CHECKPOINT+searchType+":"+r.Keyword,
+		func(page int) ([]*api.Mblog, bool, error) {
+			return cardsPage(r.api.Search(searchType, r.Keyword, page))
+		},
+	)
+}
+
+func createSearchUserWeiboService() adapter.ArchiveService {
+	keywordLabel := "Keyword"
+	keywordDesc := "the query to search users, e.g. a screen name or a part of it"
+	maxPagesLabel := "Max Pages"
+	maxPagesDesc := "max pages of results to read, unlimited by default"
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "Keyword",
+			Label:       &keywordLabel,
+			Description: &keywordDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       1,
+			Name:        "MaxPages",
+			Label:       &maxPagesLabel,
+			Description: &maxPagesDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+	}
+	options = append(options, createRawOptions(2)...)
+	return newWeiboServiceWrapper(
+		"weibo user search",
+		"get weibo users matching the keyword, each user is archived as a user record",
+		&SearchUserWeiboReader{},
+		options...,
+	)
+}
+
+// SearchUserWeiboReader of users matching keyword, ordered by relevance
+type SearchUserWeiboReader struct {
+	RawOptions
+	Keyword     string
+	MaxPages    int
+	api         *api.WeiboAPI
+	convertor   *weiboConvertor
+	retry       retryPolicy
+	err         error
+	currentPage int
+	tmp         []*model.Article
+}
+
+func (r *SearchUserWeiboReader) Init() error {
+	if len(r.Keyword) == 0 {
+		return errors.New("must provide keyword")
+	}
+	r.api = api.NewWeiboAPI()
+	r.convertor = newWeiboConvertor(r.api, newIdSet())
+	r.retry = defaultRetryPolicy
+	r.err = nil
+	r.currentPage = 0
+	r.tmp = nil
+	return r.RawOptions.apply(r.convertor)
+}
+
+func (r *SearchUserWeiboReader) Next() (*model.Article, bool) {
+	for len(r.tmp) == 0 {
+		if r.MaxPages > 0 && r.currentPage >= r.MaxPages {
+			return nil, false
+		}
+		r.currentPage++
+		var users []*api.User
+		if err := r.retry.do(func() (err error) {
+			users, err = r.api.SearchUsers(r.Keyword, r.currentPage)
+			return err
+		}); err != nil {
+			r.err = fmt.Errorf("read page %d of user search '%s' failed: %w", r.currentPage, r.Keyword, err)
+			return nil, false
+		}
+		if len(users) == 0 {
+			return nil, false
+		}
+		for _, user := range users {
+			if article := r.convertor.convertUserArticle(user); r.convertor.unseen(article) {
+				r.tmp = append(r.tmp, article)
+			}
+		}
+	}
+	rt := r.tmp[0]
+	r.tmp = r.tmp[1:]
+	return rt, true
+}
+
+// Err stopped reading, nil if all users have been read
+func (r *SearchUserWeiboReader) Err() error {
+	return r.err
+}
@@ -0,0 +1,36 @@
+package provision
+
+import (
+	"github.com/ArchiveLife/core/adapter"
+)
+
+// FallibleArticleReader could report the terminal error after iteration,
+// because 'Next' of ArticleReader has no way to report failure
+type FallibleArticleReader interface {
+	adapter.ArticleReader
+	// Err stopped the iteration, nil if all articles have been read
+	Err() error
+}
+
+// weiboServiceWrapper return the terminal error of reader from 'Run',
+// so that a failed run will not end successfully halfway
+type weiboServiceWrapper struct {
+	*adapter.GenericServiceWrapper
+	reader FallibleArticleReader
+}
+
+func newWeiboServiceWrapper(name, description string, reader FallibleArticleReader, options ...*adapter.Option) *weiboServiceWrapper {
+	return &weiboServiceWrapper{
+		adapter.NewServiceWrapper(name, description, reader, options...),
+		reader,
+	}
+}
+
+// Run with dynamic options (blocking), return the terminal error of reader,
+// every article is consumed with its access level
+func (s *weiboServiceWrapper) Run(consumer adapter.ArticleConsumer, argOptValues ...*adapter.OptionValue) error {
+	if err := s.GenericServiceWrapper.Run(withAccessLevel(consumer), argOptValues...); err != nil {
+		return err
+	}
+	return s.reader.Err()
+}
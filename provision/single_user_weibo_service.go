@@ -2,29 +2,28 @@ package provision
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"reflect"
-	"time"
 
 	"github.com/ArchiveLife/core/adapter"
 	"github.com/ArchiveLife/core/model"
 	"github.com/ArchiveLife/weibo/api"
-
-	md "github.com/JohannesKaufmann/html-to-markdown"
 )
 
 const KEY_WEIBO_ARTICLE_TYPE = "Weibo"
 const KEY_WEIBO_USER_TYPE = "WeiboUser"
 const KEY_WEIBO_RESOURCE_TYPE = "WeiboResource"
 
+const KEY_SINGLE_USER_CHECKPOINT = "weibo user:"
+
 func createSingleUserWeiboService() adapter.ArchiveService {
 	uidDesc := "the 'uid' of weibo user"
 	uidLabel := "Weibo User ID"
-	return adapter.NewServiceWrapper(
-		"weibo user",
-		"get all weibo of single user",
-		&SingleUserWeiboReader{},
-		&adapter.Option{
+	extendedProfileLabel := "Extended Profile"
+	extendedProfileDesc := "read location, birthday, education and company of user for the author of posts"
+	options := []*adapter.Option{
+		{
 			Order:       0,
 			Name:        "Uid",
 			Label:       &uidLabel,
@@ -32,94 +31,69 @@ func createSingleUserWeiboService() adapter.ArchiveService {
 			Optional:    false, // mandatory
 			ValueType:   reflect.String,
 		},
+	}
+	options = append(options, createRawOptions(1)...)
+	options = append(options, createCheckpointOptions(3)...)
+	options = append(options, createDateRangeOptions(5)...)
+	options = append(options, createContentFilterOptions(7)...)
+	options = append(options, &adapter.Option{
+		Order:       11,
+		Name:        "ExtendedProfile",
+		Label:       &extendedProfileLabel,
+		Description: &extendedProfileDesc,
+		Optional:    true,
+		ValueType:   reflect.Bool,
+	})
+	return newWeiboServiceWrapper(
+		"weibo user",
+		"get all weibo of single user",
+		&SingleUserWeiboReader{},
+		options...,
 	)
 }
 
 type SingleUserWeiboReader struct {
-	Uid         string
-	currentPage int
-	tmp         []*model.Article
-	api         *api.WeiboAPI
-	convertor   *md.Converter
+	feedReader
+	Uid             string
+	ExtendedProfile bool
 }
 
 func (r *SingleUserWeiboReader) Init() error {
-	r.api = api.NewWeiboAPI()
-	r.convertor = md.NewConverter("", true, nil)
-	r.currentPage = 0
 	if len(r.Uid) == 0 {
 		return errors.New("must provide uid")
 	}
+	if err := r.initFeed(fmt.Sprintf("user '%s'", r.Uid), KEY_SINGLE_USER_CHECKPOINT+r.Uid, r.readUserPage); err != nil {
+		return err
+	}
+	if r.ExtendedProfile {
+		r.readUserDetail()
+	}
 	return nil
 }
 
-func (r *SingleUserWeiboReader) Next() (*model.Article, bool) {
-	if len(r.tmp) > 0 {
-		rt := r.tmp[0]
-		r.tmp = r.tmp[1:]
-		return rt, true
-	}
-	r.currentPage++
-	page, err := r.api.GetUserPagesIndex(r.Uid, r.currentPage)
-	if err != nil {
-		log.Print(err)
-	}
-	if page.Ok == 1 {
-		if len(page.Data.Cards) > 0 {
-			cards := r.convertPageToArticles(page.Data.Cards)
-			if len(cards) > 0 {
-				rt := cards[0]
-				r.tmp = cards[1:]
-				return rt, true
-			}
-		}
+// readUserDetail for the authors of posts, skipped if user hides it
+func (r *SingleUserWeiboReader) readUserDetail() {
+	var detail *api.UserDetail
+	if err := r.retry.do(func() (err error) {
+		detail, err = r.api.GetUserDetail(r.Uid)
+		return err
+	}); err != nil {
+		log.Printf("read extended profile of user '%s' failed: %v", r.Uid, err)
+		return
 	}
-
-	return nil, false
+	r.convertor.details[model.CreateID(KEY_WEIBO_USER_TYPE, r.Uid)] = detail
 }
 
-func (r *SingleUserWeiboReader) convertPageToArticles(cards []api.Card) (rt []*model.Article) {
-
-	for _, card := range cards {
-		if card.Mblog != nil {
-			mblog := card.Mblog
-			article := &model.Article{
-				ID:     model.CreateID(KEY_WEIBO_ARTICLE_TYPE, mblog.ID),
-				Medias: []*model.Media{},
-			}
-			if mblog.CreatedAt != nil {
-				if createAt, err := time.Parse(time.RubyDate, *mblog.CreatedAt); err != nil {
-					article.PublishDate = &createAt
-				}
-			}
-			if mblog.Text != nil {
-				md, err := r.convertor.ConvertString(*mblog.Text)
-				if err != nil {
-					log.Println("convert md failed", err)
-					article.Content = mblog.Text
-				} else {
-					article.Content = &md
-				}
-			}
-			if mblog.User != nil {
-				user := mblog.User
-				article.Author = &model.Author{
-					ID:       model.CreateID(KEY_WEIBO_USER_TYPE, user.ID),
-					FullName: user.ScreenName,
-				}
-			}
-			if len(mblog.Pics) > 0 {
-				imageType := "image/jpg"
-				for _, pic := range mblog.Pics {
-					article.Medias = append(article.Medias, &model.Media{
-						ID:           model.CreateID(KEY_WEIBO_RESOURCE_TYPE, pic.URL),
-						MimeType:     &imageType,
-						ExternalLink: &pic.URL,
-					})
-				}
-			}
-			rt = append(rt, article)
+// readUserPage of user posts, use the server side filter container if available
+func (r *SingleUserWeiboReader) readUserPage(page int) ([]*api.Mblog, bool, error) {
+	if filter := r.filter.serverFilter(); len(filter) > 0 {
+		containerId, err := r.api.GetFilterContainerId(r.Uid, filter)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(containerId) > 0 {
+			return cardsPage(r.api.GetContainerPages(r.Uid, containerId, "filter", page))
 		}
 	}
-	return rt
+	return cardsPage(r.api.GetUserPagesIndex(r.Uid, page))
 }
@@ -0,0 +1,190 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_WEIBO_SOCIAL_GRAPH_TYPE = "WeiboSocialGraph"
+
+func createSocialGraphWeiboService() adapter.ArchiveService {
+	uidLabel := "Weibo User ID"
+	uidDesc := "the 'uid' of weibo user"
+	cookieSubLabel := "Cookie SUB"
+	cookieSubDesc := "the 'SUB' part of cookie, weibo only shows part of the lists without login"
+	maxPagesLabel := "Max Pages"
+	maxPagesDesc := "max pages of followings and followers to read, unlimited by default"
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "Uid",
+			Label:       &uidLabel,
+			Description: &uidDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       1,
+			Name:        "CookieSub",
+			Label:       &cookieSubLabel,
+			Description: &cookieSubDesc,
+			Optional:    true,
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       2,
+			Name:        "MaxPages",
+			Label:       &maxPagesLabel,
+			Description: &maxPagesDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+	}
+	options = append(options, createRawOptions(3)...)
+	return newWeiboServiceWrapper(
+		"weibo social graph",
+		"get followings and followers of single user",
+		&SocialGraphWeiboReader{},
+		options...,
+	)
+}
+
+// SocialGraphWeiboReader emit each following and follower as an user article,
+// and finally the graph article of user referencing them
+type SocialGraphWeiboReader struct {
+	RawOptions
+	Uid       string
+	CookieSub string
+	MaxPages  int
+	api       *api.WeiboAPI
+	convertor *weiboConvertor
+	retry     retryPolicy
+	err       error
+	// current list, followings first
+	refType     model.ReferenceType
+	currentPage int
+	// cursor of next page of followers, returned by the previous page
+	sinceId int64
+	// the previous page of followers is the last one
+	lastPage bool
+	tmp      []*model.Article
+	graph    *model.Article
+}
+
+func (r *SocialGraphWeiboReader) Init() error {
+	if len(r.Uid) == 0 {
+		return errors.New("must provide uid")
+	}
+	r.api = api.NewWeiboAPI()
+	r.convertor = newWeiboConvertor(r.api, newIdSet())
+	r.retry = defaultRetryPolicy
+	r.err = nil
+	r.refType = RefTypeFollowing
+	r.currentPage = 0
+	r.sinceId = 0
+	r.lastPage = false
+	r.tmp = nil
+	r.graph = nil
+	return r.RawOptions.apply(r.convertor)
+}
+
+func (r *SocialGraphWeiboReader) Next() (*model.Article, bool) {
+	for len(r.tmp) == 0 {
+		if r.graph == nil {
+			if err := r.readUser(); err != nil {
+				r.err = err
+				return nil, false
+			}
+		}
+		if r.refType == 0 {
+			return nil, false
+		}
+		if err := r.readPage(); err != nil {
+			r.err = err
+			return nil, false
+		}
+	}
+	rt := r.tmp[0]
+	r.tmp = r.tmp[1:]
+	return rt, true
+}
+
+// readUser of the graph
+func (r *SocialGraphWeiboReader) readUser() error {
+	var index *api.WeiboUserIndex
+	if err := r.retry.do(func() (err error) {
+		index, err = r.api.GetUserIndex(r.Uid)
+		return err
+	}); err != nil {
+		return fmt.Errorf("read user '%s' failed: %w", r.Uid, err)
+	}
+	info := index.Data.UserInfo
+	title := fmt.Sprintf("%s 的关注与粉丝", info.ScreenName)
+	r.graph = &model.Article{
+		ID:     model.CreateID(KEY_WEIBO_SOCIAL_GRAPH_TYPE, r.Uid),
+		Type:   KEY_WEIBO_SOCIAL_GRAPH_TYPE,
+		Title:  &title,
+		Author: convertUserInfo(&info),
+		ExtAttributes: map[string]interface{}{
+			EXT_USER_FOLLOWERS_COUNT: info.FollowersCount,
+			EXT_USER_FOLLOW_COUNT:    info.FollowCount,
+			EXT_ACCESS_LEVEL:         ACCESS_PUBLIC,
+		},
+	}
+	return nil
+}
+
+// readPage of current list, switch to the next list once current one is finished
+func (r *SocialGraphWeiboReader) readPage() error {
+	r.currentPage++
+	var users []*api.User
+	if (r.MaxPages <= 0 || r.currentPage <= r.MaxPages) && !r.lastPage {
+		var sinceId int64
+		if err := r.retry.do(func() (err error) {
+			if r.refType == RefTypeFollowing {
+				users, err = r.api.GetFollowings(r.CookieSub, r.Uid, r.currentPage)
+			} else {
+				users, sinceId, err = r.api.GetFollowers(r.CookieSub, r.Uid, r.sinceId)
+			}
+			return err
+		}); err != nil {
+			return fmt.Errorf("read page %d of user '%s' social graph failed: %w", r.currentPage, r.Uid, err)
+		}
+		if r.refType == RefTypeFollower {
+			r.sinceId = sinceId
+			r.lastPage = sinceId == 0
+		}
+	}
+	if len(users) == 0 {
+		r.currentPage = 0
+		if r.refType == RefTypeFollowing {
+			r.refType = RefTypeFollower
+		} else {
+			// all lists are finished, emit the graph at last
+			r.refType = 0
+			r.tmp = append(r.tmp, r.graph)
+		}
+		return nil
+	}
+	for _, user := range users {
+		article := r.convertor.convertUserArticle(user)
+		r.graph.References = append(r.graph.References, &model.Reference{
+			Type:        r.refType,
+			ReferenceId: string(article.Author.ID),
+		})
+		if r.convertor.unseen(article) {
+			r.tmp = append(r.tmp, article)
+		}
+	}
+	return nil
+}
+
+// Err stopped reading, nil if the social graph has been read
+func (r *SocialGraphWeiboReader) Err() error {
+	return r.err
+}
@@ -0,0 +1,214 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_WEIBO_PHOTO_TYPE = "WeiboPhoto"
+
+const KEY_VIDEO_CHECKPOINT = "weibo video:"
+
+const KEY_ALBUM_CHECKPOINT = "weibo album:"
+
+func createVideoWeiboService() adapter.ArchiveService {
+	uidLabel := "Weibo User ID"
+	uidDesc := "the 'uid' of weibo user"
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "Uid",
+			Label:       &uidLabel,
+			Description: &uidDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+	}
+	options = append(options, createRawOptions(1)...)
+	options = append(options, createCheckpointOptions(3)...)
+	options = append(options, createDateRangeOptions(5)...)
+	return newWeiboServiceWrapper(
+		"weibo user videos",
+		"get all weibo in the video tab of single user, with the videos",
+		&VideoWeiboReader{},
+		options...,
+	)
+}
+
+// VideoWeiboReader of the posts in video tab of user
+type VideoWeiboReader struct {
+	feedReader
+	Uid string
+}
+
+func (r *VideoWeiboReader) Init() error {
+	if len(r.Uid) == 0 {
+		return errors.New("must provide uid")
+	}
+	return r.initFeed(fmt.Sprintf("videos of user '%s'", r.Uid), KEY_VIDEO_CHECKPOINT+r.Uid, func(page int) ([]*api.Mblog, bool, error) {
+		return cardsPage(r.api.GetVideoPages(r.Uid, page))
+	})
+}
+
+func createAlbumWeiboService() adapter.ArchiveService {
+	uidLabel := "Weibo User ID"
+	uidDesc := "the 'uid' of weibo user"
+	maxPagesLabel := "Max Pages"
+	maxPagesDesc := "max pages of album to read, unlimited by default"
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "Uid",
+			Label:       &uidLabel,
+			Description: &uidDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+		{
+			Order:       1,
+			Name:        "MaxPages",
+			Label:       &maxPagesLabel,
+			Description: &maxPagesDesc,
+			Optional:    true,
+			ValueType:   reflect.Int,
+		},
+	}
+	options = append(options, createRawOptions(2)...)
+	options = append(options, createCheckpointOptions(4)...)
+	options = append(options, createDateRangeOptions(6)...)
+	return newWeiboServiceWrapper(
+		"weibo user album",
+		"get all photos in the album tab of single user, including the photos not posted, e.g. avatars",
+		&AlbumWeiboReader{},
+		options...,
+	)
+}
+
+// AlbumWeiboReader emit the post of each photo, or a photo article if the photo is not posted
+type AlbumWeiboReader struct {
+	feedReader
+	Uid      string
+	MaxPages int
+	author   *model.Author
+}
+
+func (r *AlbumWeiboReader) Init() error {
+	if len(r.Uid) == 0 {
+		return errors.New("must provide uid")
+	}
+	r.author = nil
+	return r.initFeed(fmt.Sprintf("album of user '%s'", r.Uid), KEY_ALBUM_CHECKPOINT+r.Uid, r.readAlbumPage)
+}
+
+// readAlbumPage of the posts of photos, the photos not posted are pending to emit after the posts
+func (r *AlbumWeiboReader) readAlbumPage(page int) ([]*api.Mblog, bool, error) {
+	if r.MaxPages > 0 && page > r.MaxPages {
+		return nil, false, nil
+	}
+	index, err := r.api.GetAlbumPages(r.Uid, page)
+	if err != nil {
+		return nil, false, err
+	}
+	photos := index.Photos()
+	// weibo responds 'ok: 0' after the last page
+	if index.Ok != 1 || len(photos) == 0 {
+		return nil, false, nil
+	}
+	mblogs, err := r.splitPhotos(photos)
+	if err != nil {
+		return nil, false, err
+	}
+	return mblogs, true, nil
+}
+
+// splitPhotos of page into the posts of photos, and the photos not posted which are pending
+// to emit after the posts
+func (r *AlbumWeiboReader) splitPhotos(photos []*api.CardPic) (mblogs []*api.Mblog, err error) {
+	for i, photo := range photos {
+		if isPosted(photo) {
+			mblogs = append(mblogs, photo.Mblog)
+			continue
+		}
+		if !r.acceptPhoto(photos, i) {
+			continue
+		}
+		article, err := r.convertPhoto(photo)
+		if err != nil {
+			return nil, err
+		}
+		if article != nil && r.convertor.unseen(article) {
+			r.pending = append(r.pending, article)
+		}
+	}
+	return mblogs, nil
+}
+
+func isPosted(photo *api.CardPic) bool {
+	return photo.Mblog != nil && photo.Mblog.ID != nil
+}
+
+// acceptPhoto not posted, which has no date or id, so it is dated and ordered by the nearest
+// posted photos in album, the newer one before it and the older one after it
+func (r *AlbumWeiboReader) acceptPhoto(photos []*api.CardPic, i int) bool {
+	var newer, older *api.Mblog
+	for j := i - 1; j >= 0 && newer == nil; j-- {
+		if isPosted(photos[j]) {
+			newer = photos[j].Mblog
+		}
+	}
+	for j := i + 1; j < len(photos) && older == nil; j++ {
+		if isPosted(photos[j]) {
+			older = photos[j].Mblog
+		}
+	}
+	if newer != nil && r.period.tooOld(mblogPublishDate(newer)) {
+		return false
+	}
+	if older != nil && r.period.tooNew(mblogPublishDate(older)) {
+		return false
+	}
+	if !r.inHead() {
+		return true
+	}
+	// in head of incremental mode, only the photos newer than the archived posts are emitted
+	if newer != nil {
+		return isNewer(stringOf(newer.ID), r.archivedID)
+	}
+	return older != nil && isNewer(stringOf(older.ID), r.archivedID)
+}
+
+// convertPhoto not posted, keyed by the pic id which is the same for all sizes and hosts of photo
+func (r *AlbumWeiboReader) convertPhoto(photo *api.CardPic) (*model.Article, error) {
+	link := photo.PicBig
+	if len(link) == 0 {
+		link = photo.PicMiddle
+	}
+	if len(photo.PicID) == 0 || len(link) == 0 {
+		return nil, nil
+	}
+	if r.author == nil {
+		index, err := r.api.GetUserIndex(r.Uid)
+		if err != nil {
+			return nil, fmt.Errorf("read user '%s' failed: %w", r.Uid, err)
+		}
+		r.author = convertUserInfo(&index.Data.UserInfo)
+	}
+	imageType := "image/jpg"
+	return &model.Article{
+		ID:     model.CreateID(KEY_WEIBO_PHOTO_TYPE, photo.PicID),
+		Type:   KEY_WEIBO_PHOTO_TYPE,
+		Author: r.author,
+		Medias: []*model.Media{{
+			ID:           model.CreateID(KEY_WEIBO_RESOURCE_TYPE, photo.PicID),
+			MimeType:     &imageType,
+			ExternalLink: &link,
+		}},
+		// the photos in album tab are visible to anyone
+		ExtAttributes: map[string]interface{}{EXT_ACCESS_LEVEL: ACCESS_PUBLIC},
+	}, nil
+}
@@ -0,0 +1,87 @@
+package provision
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlbumWeiboReader_ConvertPhoto(t *testing.T) {
+	assert := assert.New(t)
+
+	r := &AlbumWeiboReader{Uid: "1", author: &model.Author{}}
+	large, err := r.convertPhoto(&api.CardPic{PicID: "abc", PicBig: "https://wx1.sinaimg.cn/large/abc.jpg"})
+	assert.Nil(err)
+	middle, err := r.convertPhoto(&api.CardPic{PicID: "abc", PicMiddle: "https://wx2.sinaimg.cn/bmiddle/abc.jpg"})
+	assert.Nil(err)
+	// the same photo from another host or in another size
+	assert.Equal(large.ID, middle.ID)
+	assert.Equal(KEY_WEIBO_PHOTO_TYPE, large.Type)
+	assert.Equal("https://wx1.sinaimg.cn/large/abc.jpg", *large.Medias[0].ExternalLink)
+
+	missing, err := r.convertPhoto(&api.CardPic{PicBig: "https://wx1.sinaimg.cn/large/abc.jpg"})
+	assert.Nil(err)
+	assert.Nil(missing)
+}
+
+// photosOf album, the positive ids are posted photos published on the id-th day of 2026,
+// the others are photos not posted
+func photosOf(ids ...int) (rt []*api.CardPic) {
+	for _, id := range ids {
+		photo := &api.CardPic{PicID: fmt.Sprintf("pic%d", id), PicBig: fmt.Sprintf("https://wx1.sinaimg.cn/large/pic%d.jpg", id)}
+		if id > 0 {
+			photo.Mblog = mblogOf(id)
+		}
+		rt = append(rt, photo)
+	}
+	return rt
+}
+
+func pendingPhotos(t *testing.T, r *AlbumWeiboReader, photos []*api.CardPic) (rt []string) {
+	r.author = &model.Author{}
+	r.convertor = newWeiboConvertor(api.NewWeiboAPI(), newIdSet())
+	r.pending = nil
+	if _, err := r.splitPhotos(photos); err != nil {
+		t.Fatal(err)
+	}
+	for _, article := range r.pending {
+		rt = append(rt, *article.Medias[0].ExternalLink)
+	}
+	return rt
+}
+
+func TestAlbumWeiboReader_PendingPhotos(t *testing.T) {
+	assert := assert.New(t)
+
+	link := func(id int) string { return fmt.Sprintf("https://wx1.sinaimg.cn/large/pic%d.jpg", id) }
+	photos := photosOf(-1, 9, -2, 7, -3, 5, -4)
+
+	r := &AlbumWeiboReader{}
+	assert.Equal([]string{link(-1), link(-2), link(-3), link(-4)}, pendingPhotos(t, r, photos))
+	// the posted photos are read as posts, and the emitted photos are not pending again
+	mblogs, err := r.splitPhotos(photos)
+	assert.Nil(err)
+	assert.Len(mblogs, 3)
+	assert.Len(r.pending, 4)
+
+	// the photos are dated by the posted photos around
+	r = &AlbumWeiboReader{}
+	if r.period, err = (&DateRangeOptions{Since: "2026-01-06", Until: "2026-01-08"}).dateRange(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal([]string{link(-2), link(-3)}, pendingPhotos(t, r, photos))
+
+	// the photos older than the archived posts are not emitted again in incremental mode
+	r = &AlbumWeiboReader{}
+	r.Incremental = true
+	r.archivedID = "7"
+	r.checkpoint = &Checkpoint{NewestID: "7"}
+	assert.Equal([]string{link(-1), link(-2)}, pendingPhotos(t, r, photos))
+	// the photos could not be told from the archived ones without posted photos around
+	assert.Len(pendingPhotos(t, r, photosOf(-1, -2)), 0)
+	r.reachedArchived = true
+	assert.Len(pendingPhotos(t, r, photosOf(-1, -2)), 2)
+}
@@ -0,0 +1,150 @@
+package provision
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ArchiveLife/core/adapter"
+	"github.com/ArchiveLife/core/model"
+	"github.com/ArchiveLife/weibo/api"
+)
+
+const KEY_WEIBO_TOPIC_TYPE = "WeiboTopic"
+
+// ext attributes of topic header
+const (
+	EXT_TOPIC_CONTAINER_ID     = "topic_container_id"
+	EXT_TOPIC_READ_COUNT       = "topic_read_count"
+	EXT_TOPIC_DISCUSSION_COUNT = "topic_discussion_count"
+	EXT_TOPIC_HOST             = "topic_host"
+	EXT_TOPIC_SUPER            = "topic_super"
+)
+
+func createTopicWeiboService() adapter.ArchiveService {
+	topicLabel := "Topic"
+	topicDesc := "the hashtag topic, e.g. '#topic#', or the container id of super topic (超话) which starts with '100808'"
+	options := []*adapter.Option{
+		{
+			Order:       0,
+			Name:        "Topic",
+			Label:       &topicLabel,
+			Description: &topicDesc,
+			Optional:    false, // mandatory
+			ValueType:   reflect.String,
+		},
+	}
+	// posts of topic are not ordered by time, so the topic is not checkpointed
+	options = append(options, createRawOptions(1)...)
+	options = append(options, createDateRangeOptions(3)...)
+	options = append(options, createContentFilterOptions(5)...)
+	return newWeiboServiceWrapper(
+		"weibo topic",
+		"get all weibo of hashtag topic or super topic, with the header info of topic",
+		&TopicWeiboReader{},
+		options...,
+	)
+}
+
+// TopicWeiboReader emit the header of topic first, then the posts of topic feed,
+// the feed is ordered by popularity or reply time instead of publish time
+type TopicWeiboReader struct {
+	feedReader
+	Topic       string
+	containerId string
+	// since id of pages, the feed of super topic is paged by the since id of previous page
+	sinceIds map[int]int64
+	// header of topic to emit
+	header *model.Article
+}
+
+func (r *TopicWeiboReader) Init() error {
+	topic := strings.TrimSpace(r.Topic)
+	if len(strings.Trim(topic, "#")) == 0 {
+		return errors.New("must provide topic")
+	}
+	if api.IsSuperTopic(topic) {
+		r.containerId = api.SuperTopicContainerId(topic)
+	} else {
+		r.containerId = api.TopicContainerId(topic)
+	}
+	r.sinceIds = map[int]int64{}
+	r.header = nil
+	r.unordered = true
+	return r.initFeed(fmt.Sprintf("topic '%s'", topic), "", r.readTopicPage)
+}
+
+func (r *TopicWeiboReader) readTopicPage(page int) ([]*api.Mblog, bool, error) {
+	sinceId := ""
+	if id, found := r.sinceIds[page]; found {
+		sinceId = fmt.Sprint(id)
+	}
+	index, err := r.api.GetTopicPages(r.containerId, sinceId, page)
+	if err != nil {
+		return nil, false, err
+	}
+	if index.Data.CardlistInfo.SinceID != 0 {
+		r.sinceIds[page+1] = index.Data.CardlistInfo.SinceID
+	}
+	if page == 1 && index.Data.PageInfo != nil {
+		r.header = r.convertTopicHeader(index.Data.PageInfo)
+	}
+	return cardsPage(index, nil)
+}
+
+func (r *TopicWeiboReader) Next() (*model.Article, bool) {
+	article, next := r.feedReader.Next()
+	if r.header == nil {
+		return article, next
+	}
+	header := r.header
+	r.header = nil
+	if article != nil {
+		r.tmp = append([]*model.Article{article}, r.tmp...)
+		next = true
+	}
+	return header, next
+}
+
+// convertTopicHeader as an article, the id is the same for each run so the counts are updated
+func (r *TopicWeiboReader) convertTopicHeader(info *api.TopicPageInfo) *model.Article {
+	title := info.PageTitle
+	if len(title) == 0 {
+		title = r.Topic
+	}
+	lines := []string{fmt.Sprintf("# %s", title), ""}
+	for _, desc := range append([]string{info.Desc, info.DetailDesc}, info.DescMore...) {
+		if len(desc) > 0 {
+			lines = append(lines, desc, "")
+		}
+	}
+	content := strings.Join(lines, "\n")
+	ext := extAttributes{
+		EXT_TOPIC_CONTAINER_ID:     r.containerId,
+		EXT_TOPIC_READ_COUNT:       info.ReadCount(),
+		EXT_TOPIC_DISCUSSION_COUNT: info.DiscussionCount(),
+		EXT_TOPIC_SUPER:            api.IsSuperTopic(r.containerId),
+		EXT_ACCESS_LEVEL:           ACCESS_PUBLIC,
+	}
+	host := info.Host()
+	ext.setString(EXT_TOPIC_HOST, &host)
+	article := &model.Article{
+		ID:            model.CreateID(KEY_WEIBO_TOPIC_TYPE, r.containerId),
+		Type:          KEY_WEIBO_TOPIC_TYPE,
+		Title:         &title,
+		Content:       &content,
+		Medias:        []*model.Media{},
+		ExtAttributes: ext,
+	}
+	if len(info.Portrait) > 0 {
+		imageType := "image/jpg"
+		link := info.Portrait
+		article.Medias = append(article.Medias, &model.Media{
+			ID:           model.CreateID(KEY_WEIBO_RESOURCE_TYPE, link),
+			MimeType:     &imageType,
+			ExternalLink: &link,
+		})
+	}
+	return article
+}